@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/husky/fake"
 	"github.com/ice-blockchain/wintr/analytics/tracking"
 	appcfg "github.com/ice-blockchain/wintr/config"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
@@ -30,9 +31,13 @@ func StartProcessor(ctx context.Context, cancel context.CancelFunc) Processor {
 	)
 	db = storage.MustConnect(ctx, ddl, applicationYamlKey)
 	prc.repository = &repository{
-		cfg:            &cfg,
-		db:             db,
-		trackingClient: tracking.New(applicationYamlKey),
+		cfg: &cfg,
+		db:  db,
+	}
+	if cfg.FakeProviders {
+		prc.trackingClient = fake.NewTrackingClient(applicationYamlKey)
+	} else {
+		prc.trackingClient = tracking.New(applicationYamlKey)
 	}
 	//nolint:contextcheck // It's intended. Cuz we want to close everything gracefully.
 	mbConsumer = messagebroker.MustConnectAndStartConsuming(context.Background(), cancel, applicationYamlKey,
@@ -65,5 +65,8 @@ type (
 	}
 	config struct {
 		messagebroker.Config `mapstructure:",squash"` //nolint:tagliatelle // Nope.
+		// If set, the actions and the user attributes are tracked by the in-process fake of the provider, instead of the real one.
+		// I.E. for integration tests.
+		FakeProviders bool `yaml:"fakeProviders"`
 	}
 )
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ice-blockchain/husky/notifications"
+)
+
+const (
+	// The limits of what iOS and Android show, for a collapsed notification.
+	defaultMaxTitleLength = 65
+	defaultMaxBodyLength  = 178
+)
+
+//nolint:gochecknoglobals // Because those are flags.
+var (
+	translationsDir = flag.String("dir", "", "the `translations` directory to lint; the ones embedded in the build are used, if not specified")
+	maxTitleLength  = flag.Int("maxTitleLength", defaultMaxTitleLength, "the maximum length of the rendered titles, in characters")
+	maxBodyLength   = flag.Int("maxBodyLength", defaultMaxBodyLength, "the maximum length of the rendered bodies, in characters")
+	strict          = flag.Bool("strict", false, "whether warnings (missing languages and over-long texts) fail it too")
+)
+
+// It lints every push notification template, of every notification type, in every language, and exits with 1 if it found errors.
+func main() {
+	flag.Parse()
+	translations := notifications.EmbeddedTranslations()
+	if *translationsDir != "" {
+		translations = os.DirFS(*translationsDir)
+	}
+	problems := notifications.LintTranslations(translations, &notifications.TranslationLintOptions{
+		MaxTitleLength: *maxTitleLength,
+		MaxBodyLength:  *maxBodyLength,
+	})
+	var errs, warnings int
+	for _, problem := range problems {
+		if problem.Severity == notifications.ErrorTranslationProblemSeverity {
+			errs++
+		} else {
+			warnings++
+		}
+		location := fmt.Sprintf("%v/%v", problem.NotificationType, problem.Language)
+		if problem.Field != "" {
+			location += "." + problem.Field
+		}
+		fmt.Printf("%-7v %v: %v\n", problem.Severity, location, problem.Message) //nolint:forbidigo // It's a CLI.
+	}
+	fmt.Printf("%v errors, %v warnings\n", errs, warnings) //nolint:forbidigo // It's a CLI.
+	if errs != 0 || (*strict && warnings != 0) {
+		os.Exit(1)
+	}
+}
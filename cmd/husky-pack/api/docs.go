@@ -20,9 +20,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/inapp-notifications-user-auth-token": {
-            "put": {
-                "description": "Generates a new token for the user to be used to connect to the inApp notifications stream on behalf of the user.",
+        "/captured-notifications": {
+            "get": {
+                "description": "Returns the notifications that were captured, instead of being sent, by the processors running in capture mode, newest first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -40,13 +40,45 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "push",
+                            "email",
+                            "inapp"
+                        ],
+                        "type": "string",
+                        "description": "the notification channel",
+                        "name": "notificationChannel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the device token or the subscription topic, the email address, the user ID or the in-app feed",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements to skip before starting to look for",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/notifications.InAppNotificationsUserAuthToken"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notifications.CapturedNotification"
+                            }
                         }
                     },
                     "400": {
@@ -88,56 +120,132 @@ const docTemplate = `{
                 }
             }
         },
-        "/news": {
+        "/email-events": {
             "post": {
-                "description": "Creates a news article, for each specified language.",
+                "description": "Webhook for the bounce and complaint events of the email provider. The email addresses that permanently bounced or complained are not emailed anymore, until the user changes their email address.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "News"
+                    "Notifications"
                 ],
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "Bearer \u003cAdd access token here\u003e",
-                        "description": "Insert your access token",
-                        "name": "Authorization",
+                        "description": "The configured webhook secret",
+                        "name": "X-Webhook-Secret",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Required, if ` + "`" + `newsImportFile` + "`" + ` param is not specified.",
-                        "name": "news",
-                        "in": "formData"
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.ProcessEmailEventsRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if the secret is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
                     },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/email-unsubscriptions": {
+            "get": {
+                "description": "Renders the page the unsubscribe links in the emails open. It changes nothing, it only asks the user to confirm, via ` + "`" + `POST /email-unsubscriptions` + "`" + `.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
                     {
-                        "type": "file",
-                        "description": "The image for the news article",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "The token from the unsubscribe link",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "A json file with an array of all language variants for 1 news article",
-                        "name": "newsImportFile",
-                        "in": "formData"
+                        "type": "boolean",
+                        "description": "Unsubscribe from all emails, not just the ones of the domain the link was generated for",
+                        "name": "all",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "the confirmation page",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/main.News"
-                            }
+                            "type": "string"
                         }
+                    }
+                }
+            },
+            "post": {
+                "description": "Unsubscribes the user the token was generated for from emails. It requires no authorization, because it's used by the unsubscribe links in the emails. The emails have no ` + "`" + `List-Unsubscribe` + "`" + ` headers yet, so it's not an RFC 8058 one-click unsubscribe endpoint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The token from the unsubscribe link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Unsubscribe from all emails, not just the ones of the domain the link was generated for",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok"
                     },
                     "400": {
                         "description": "if validations fail",
@@ -145,20 +253,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "if not authorized",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
                     "403": {
-                        "description": "not allowed",
+                        "description": "if the token is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "if it conflicts with existing news articles",
+                    "404": {
+                        "description": "if user not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -184,9 +286,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/news/{language}/{newsId}": {
-            "delete": {
-                "description": "Deletes a language variant of a news article",
+        "/inapp-notifications-user-auth-token": {
+            "put": {
+                "description": "Generates a new token for the user to be used to connect to the inApp notifications stream on behalf of the user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -194,7 +296,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "News"
+                    "Notifications"
                 ],
                 "parameters": [
                     {
@@ -204,28 +306,14 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID of the news article",
-                        "name": "newsId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "the language code",
-                        "name": "language",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK - found and deleted"
-                    },
-                    "204": {
-                        "description": "No Content - already deleted"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.InAppNotificationsUserAuthToken"
+                        }
                     },
                     "400": {
                         "description": "if validations fail",
@@ -264,9 +352,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Modifies a language variant of a news article",
+            }
+        },
+        "/news": {
+            "post": {
+                "description": "Creates a news article, for each specified language.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -285,75 +375,40 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "ID of the news article",
-                        "name": "newsId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "The language of the news article",
-                        "name": "language",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Optional. Setting this will save you from race conditions. Example:` + "`" + `1232412415326543647657` + "`" + `.",
-                        "name": "checksum",
-                        "in": "formData"
-                    },
                     {
                         "type": "boolean",
-                        "name": "markViewed",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Optional. Example: ` + "`" + `financial` + "`" + `.",
-                        "name": "tags",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Optional.",
-                        "name": "title",
-                        "in": "formData"
-                    },
-                    {
-                        "enum": [
-                            "regular",
-                            "featured"
-                        ],
-                        "type": "string",
-                        "description": "Optional. Example: any of ` + "`" + `regular` + "`" + `, ` + "`" + `featured` + "`" + `.",
-                        "name": "type",
+                        "description": "Optional. Set it to ` + "`" + `true` + "`" + ` to skip the near-duplicate title detection.",
+                        "name": "allowSimilarTitles",
                         "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "Optional. Example: ` + "`" + `https://somewebsite.com/blockchain` + "`" + `.",
-                        "name": "url",
+                        "description": "Required, if ` + "`" + `newsImportFile` + "`" + ` param is not specified.",
+                        "name": "news",
                         "in": "formData"
                     },
                     {
                         "type": "file",
                         "description": "The image for the news article",
                         "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "A json file with an array of all language variants for 1 news article",
+                        "name": "newsImportFile",
                         "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/main.News"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/main.News"
+                            }
                         }
                     },
                     "400": {
@@ -369,19 +424,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "if not allowed",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "if news or user not found",
+                        "description": "not allowed",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "if conflict occurs",
+                        "description": "if it conflicts with existing news articles(same url or a similar title); the conflicting article is in ` + "`" + `data.news` + "`" + `",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -407,9 +456,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification-channels/{notificationChannel}/toggles/{type}": {
-            "put": {
-                "description": "Toggles the specific notification channel toggle type on/off.",
+        "/news/featured": {
+            "get": {
+                "description": "Returns the published, untargeted featured ` + "`" + `en` + "`" + ` news articles, in the order they're shown to the users.",
                 "consumes": [
                     "application/json"
                 ],
@@ -417,7 +466,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Notifications"
+                    "News"
                 ],
                 "parameters": [
                     {
@@ -427,50 +476,89 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Request params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.ToggleNotificationChannelDomainRequestBody"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/news.TaggedNews"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Reorders the featured news articles. The ones pinned are still shown first, until their pin expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
                     {
-                        "enum": [
-                            "push",
-                            "email"
-                        ],
                         "type": "string",
-                        "description": "name of the channel",
-                        "name": "notificationChannel",
-                        "in": "path",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "enum": [
-                            "disable_all",
-                            "weekly_report",
-                            "weekly_stats",
-                            "achievements",
-                            "promotions",
-                            "news",
-                            "micro_community",
-                            "mining",
-                            "daily_bonus",
-                            "system"
-                        ],
-                        "type": "string",
-                        "description": "the type of the toggle",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.ReorderFeaturedNewsRequestBody"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ok"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/news.TaggedNews"
+                            }
+                        }
                     },
                     "400": {
                         "description": "if validations fail",
@@ -484,8 +572,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "if user not found",
+                        "description": "if at least one of the news is not a published, untargeted featured news article",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -511,9 +605,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user-pings/{userId}": {
-            "post": {
-                "description": "Pings the user.",
+        "/news/stats/{newsId}": {
+            "get": {
+                "description": "Returns the daily engagement with a news article, per language: new viewers, opens by source and ` + "`" + `news_added` + "`" + ` announcements sent.",
                 "consumes": [
                     "application/json"
                 ],
@@ -521,7 +615,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Notifications"
+                    "News"
                 ],
                 "parameters": [
                     {
@@ -534,15 +628,30 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID of the user to ping",
-                        "name": "userId",
+                        "description": "ID of the news article",
+                        "name": "newsId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inclusive. Defaults to 30 days before ` + "`" + `to` + "`" + `. Example ` + "`" + `2022-01-03T16:20:52.156534Z` + "`" + `.",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exclusive. Defaults to now. Example ` + "`" + `2022-02-03T16:20:52.156534Z` + "`" + `.",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "accepted"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.NewsStats"
+                        }
                     },
                     "400": {
                         "description": "if validations fail",
@@ -557,19 +666,91 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "not allowed",
+                        "description": "if not allowed",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "if user not found",
+                    "422": {
+                        "description": "if syntax fails",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "if already pinged and need to try later",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/news/stats/{newsId}/csv": {
+            "get": {
+                "description": "Same as ` + "`" + `GET /news/stats/{newsId}` + "`" + `, but it returns the daily series as CSV.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the news article",
+                        "name": "newsId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inclusive. Defaults to 30 days before ` + "`" + `to` + "`" + `. Example ` + "`" + `2022-01-03T16:20:52.156534Z` + "`" + `.",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exclusive. Defaults to now. Example ` + "`" + `2022-02-03T16:20:52.156534Z` + "`" + `.",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "the CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -594,57 +775,2289 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "main.News": {
-            "type": "object",
-            "properties": {
-                "checksum": {
-                    "type": "string",
-                    "example": "1232412415326543647657"
-                },
-                "createdAt": {
-                    "type": "string",
-                    "example": "2022-01-03T16:20:52.156534Z"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
-                },
-                "imageUrl": {
-                    "type": "string",
-                    "example": "https://somewebsite.com/blockchain.jpg"
-                },
-                "language": {
-                    "type": "string",
-                    "example": "en"
-                },
-                "notificationChannels": {
-                    "type": "array",
-                    "items": {
+        },
+        "/news/translation-coverage": {
+            "get": {
+                "description": "Returns, for each ` + "`" + `en` + "`" + ` news article, the languages it is translated into, the translations that are stale and the ones that are missing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
                         "type": "string",
-                        "enum": [
-                            "inapp",
-                            "sms",
-                            "email",
-                            "push",
-                            "analytics",
-                            "push||analytics",
-                            "push||email",
-                            "push||email||analytics"
-                        ]
-                    }
-                },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "The expected languages. If unspecified, every language that has at least one news article is expected.",
+                        "name": "languages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements to skip before starting to look for",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/news.TranslationCoverage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/news/{language}/{newsId}": {
+            "delete": {
+                "description": "Deletes a language variant of a news article",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the news article",
+                        "name": "newsId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the language code",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - found and deleted"
+                    },
+                    "204": {
+                        "description": "No Content - already deleted"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Modifies a language variant of a news article",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the news article",
+                        "name": "newsId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The language of the news article",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Optional. Set it to ` + "`" + `true` + "`" + ` to skip the near-duplicate title detection.",
+                        "name": "allowSimilarTitles",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional. Setting this will save you from race conditions. Example:` + "`" + `1232412415326543647657` + "`" + `.",
+                        "name": "checksum",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Optional. Set it to ` + "`" + `false` + "`" + ` to publish a draft.",
+                        "name": "draft",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "markViewed",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional. Only for ` + "`" + `featured` + "`" + ` news. Use a date in the past to unpin it. Example: ` + "`" + `2022-01-03T16:20:52.156534Z` + "`" + `.",
+                        "name": "pinnedUntil",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Optional. Only for ` + "`" + `featured` + "`" + ` news. The ones with higher priorities are shown first.",
+                        "name": "priority",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "push",
+                            "inapp",
+                            "organic"
+                        ],
+                        "type": "string",
+                        "description": "Optional. Only used with ` + "`" + `markViewed` + "`" + `. It's the ` + "`" + `contentSource` + "`" + ` of the deeplink the user came from, if any.",
+                        "name": "source",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Optional. Example: ` + "`" + `financial` + "`" + `.",
+                        "name": "tags",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional. JSON. Use ` + "`" + `{}` + "`" + ` to remove it. Example: ` + "`" + `{\"countries\":[\"US\",\"RO\"],\"segments\":[\"has_referrals\"],\"minAppVersion\":\"1.2.0\"}` + "`" + `.",
+                        "name": "targeting",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional.",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "regular",
+                            "featured"
+                        ],
+                        "type": "string",
+                        "description": "Optional. Example: any of ` + "`" + `regular` + "`" + `, ` + "`" + `featured` + "`" + `.",
+                        "name": "type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional. Example: ` + "`" + `https://somewebsite.com/blockchain` + "`" + `.",
+                        "name": "url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional. JSON. Use ` + "`" + `[]` + "`" + ` to stop the experiment. Example: ` + "`" + `[{\"id\":\"b\",\"title\":\"Why blockchain matters\"}]` + "`" + `.",
+                        "name": "variants",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "The image for the news article",
+                        "name": "image",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.News"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if news or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "if conflict occurs(same url or a similar title); the conflicting article is in ` + "`" + `data.news` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/news/{language}/{newsId}/experiment-report": {
+            "get": {
+                "description": "Returns the views and reactions of each title/image variant of a language variant of a news article, and which one is winning.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the news article",
+                        "name": "newsId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The language of the news article",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/news.ExperimentReport"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if news not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/news/{language}/{newsId}/reactions": {
+            "put": {
+                "description": "Sets the authorized user's reaction to a language variant of a news article, replacing the previous one, if any.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the news article",
+                        "name": "newsId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The language of the news article",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.SetNewsReactionRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if news not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the authorized user's reaction from a language variant of a news article.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the news article",
+                        "name": "newsId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The language of the news article",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - found and deleted"
+                    },
+                    "204": {
+                        "description": "No Content - already deleted"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-channels/{notificationChannel}/notification-types/{notificationType}": {
+            "put": {
+                "description": "Toggles a specific notification type on/off, for a notification channel, overriding the toggle of its domain. ` + "`" + `disable_all` + "`" + ` still takes precedence.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.ToggleNotificationChannelNotificationTypeRequestBody"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "push",
+                            "email"
+                        ],
+                        "type": "string",
+                        "description": "name of the channel",
+                        "name": "notificationChannel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "daily_bonus",
+                            "new_contact",
+                            "new_referral",
+                            "news_added",
+                            "ping",
+                            "level_badge_unlocked",
+                            "coin_badge_unlocked",
+                            "social_badge_unlocked",
+                            "role_changed",
+                            "level_changed",
+                            "mining_session_expiring",
+                            "mining_session_expired",
+                            "re_engagement_step_1",
+                            "re_engagement_step_2",
+                            "re_engagement_step_3"
+                        ],
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the toggle of a specific notification type, for a notification channel, so that it follows the toggle of its domain again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "push",
+                            "email"
+                        ],
+                        "type": "string",
+                        "description": "name of the channel",
+                        "name": "notificationChannel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "daily_bonus",
+                            "new_contact",
+                            "new_referral",
+                            "news_added",
+                            "ping",
+                            "level_badge_unlocked",
+                            "coin_badge_unlocked",
+                            "social_badge_unlocked",
+                            "role_changed",
+                            "level_changed",
+                            "mining_session_expiring",
+                            "mining_session_expired",
+                            "re_engagement_step_1",
+                            "re_engagement_step_2",
+                            "re_engagement_step_3"
+                        ],
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-channels/{notificationChannel}/toggles": {
+            "put": {
+                "description": "Sets multiple notification channel toggles at once, atomically, and returns the resulting state of all of them, ` + "`" + `disable_all` + "`" + ` included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.SetNotificationChannelTogglesRequestBody"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "push",
+                            "email"
+                        ],
+                        "type": "string",
+                        "description": "name of the channel",
+                        "name": "notificationChannel",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notifications.NotificationChannelToggle"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-channels/{notificationChannel}/toggles/{type}": {
+            "put": {
+                "description": "Toggles the specific notification channel toggle type on/off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.ToggleNotificationChannelDomainRequestBody"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "push",
+                            "email"
+                        ],
+                        "type": "string",
+                        "description": "name of the channel",
+                        "name": "notificationChannel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "disable_all",
+                            "weekly_report",
+                            "weekly_stats",
+                            "achievements",
+                            "promotions",
+                            "news",
+                            "micro_community",
+                            "mining",
+                            "daily_bonus",
+                            "system"
+                        ],
+                        "type": "string",
+                        "description": "the type of the toggle",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/push-notification-templates/{notificationType}": {
+            "get": {
+                "description": "Returns, for every language, the version of the push notification template of the notification type that's used. Version ` + "`" + `0` + "`" + ` is the one the service is built with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notifications.PushNotificationTemplate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/push-notification-templates/{notificationType}/{language}": {
+            "get": {
+                "description": "Returns every version of the push notification template of the notification type, in the language, newest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the language of the template",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notifications.PushNotificationTemplate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if there's no template",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new version of the push notification template of the notification type, in the language. It's not used until it's published.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the language of the template",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreatePushNotificationTemplateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.PushNotificationTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "if another version was created in the meantime",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails or the template is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/push-notification-templates/{notificationType}/{language}/published": {
+            "put": {
+                "description": "Starts using a version of the push notification template of the notification type, in the language, instead of the current one. Publish an older version to roll back to it, or ` + "`" + `0` + "`" + ` to go back to the one the service is built with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the language of the template",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.PublishPushNotificationTemplateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if the version is not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/push-notification-templates/{notificationType}/{language}/versions/{version}/preview": {
+            "post": {
+                "description": "Renders a version of the push notification template, for the provided data or, if not provided, for sample data.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the language of the template",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the version of the template. ` + "`" + `0` + "`" + ` is the one the service is built with",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.PreviewPushNotificationTemplateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.PushNotificationTemplatePreview"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if the version is not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails or it can't be rendered with the data",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/test-notifications/{notificationType}": {
+            "post": {
+                "description": "Renders the push, in-app and email notifications of the notification type for the user, exactly as they'd be sent for the message their source consumes, which is the ` + "`" + `data` + "`" + `. Unless it's a ` + "`" + `dryRun` + "`" + `, they're sent to the user too, even if they were already sent, without preventing the real ones from being sent later on.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "daily_bonus",
+                            "new_contact",
+                            "new_referral",
+                            "ping",
+                            "level_badge_unlocked",
+                            "coin_badge_unlocked",
+                            "social_badge_unlocked",
+                            "role_changed",
+                            "level_changed"
+                        ],
+                        "type": "string",
+                        "description": "the notification type",
+                        "name": "notificationType",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.SendTestNotificationRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.TestNotification"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails or the notification type is not supported",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-pings": {
+            "post": {
+                "description": "Pings all the referrals of the user that can be pinged, I.E. the ones whose ping cooldown ended. At most 1000 are pinged at once. Up to 1000 of the skipped ones are returned as well, with the ` + "`" + `skipReason` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notifications.UserPingResult"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-pings/{userId}": {
+            "post": {
+                "description": "Pings the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user to ping",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "accepted"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "if already pinged and need to try later",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "inapp.Parcel": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "broadcast_news"
+                },
+                "actor": {
+                    "$ref": "#/definitions/internal.ID"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "referenceId": {
+                    "type": "string",
+                    "example": "e5335afb-8ec4-4669-953d-37f0c712ba8d"
+                },
+                "subject": {
+                    "$ref": "#/definitions/internal.ID"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                }
+            }
+        },
+        "internal.ID": {
+            "type": "object",
+            "properties": {
+                "type": {
+                    "type": "string",
+                    "example": "userId"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "e5335afb-8ec4-4669-953d-37f0c712ba8d"
+                }
+            }
+        },
+        "main.CreatePushNotificationTemplateRequestBody": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "Required. ICU MessageFormat, with the same placeholders as the ` + "`" + `en` + "`" + ` template the service is built with.",
+                    "type": "string",
+                    "example": "Start mining, to not lose your streak!"
+                },
+                "title": {
+                    "description": "Required. ICU MessageFormat, with the same placeholders as the ` + "`" + `en` + "`" + ` template the service is built with.",
+                    "type": "string",
+                    "example": "{Username} pinged you"
+                }
+            }
+        },
+        "main.News": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "type": "string",
+                    "example": "1232412415326543647657"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "draft": {
+                    "description": "Drafts are not visible to users and nobody is notified about them until they're published.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "imageUrl": {
+                    "type": "string",
+                    "example": "https://somewebsite.com/blockchain.jpg"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notificationChannels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "inapp",
+                            "sms",
+                            "email",
+                            "push",
+                            "analytics",
+                            "push||analytics",
+                            "push||email",
+                            "push||email||analytics"
+                        ]
+                    }
+                },
+                "pinnedUntil": {
+                    "description": "Only for ` + "`" + `featured` + "`" + ` news. Until then, it's shown before the other featured news, regardless of priority.",
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "priority": {
+                    "description": "Only for ` + "`" + `featured` + "`" + ` news. The ones with higher priorities are shown first.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "cats",
+                        "dogs",
+                        "frogs"
+                    ]
+                },
+                "targeting": {
+                    "description": "If set, only the users matching it are going to see it/get notified about it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.Targeting"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The importance of the blockchain technology"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/news.Type"
+                        }
+                    ],
+                    "example": "regular"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://somewebsite.com/blockchain"
+                },
+                "variants": {
+                    "description": "If set, users are split evenly and deterministically between the original title/image and each of these.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/news.Variant"
+                    }
+                },
+                "views": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "main.NewsConversion": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notificationChannel": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.NotificationChannel"
+                        }
+                    ],
+                    "example": "push"
+                },
+                "opens": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "rate": {
+                    "description": "Opens/Sent. Push announcements broadcasted via topics are counted once per topic, so it's only meaningful for comparisons.",
+                    "type": "number",
+                    "example": 0.5
+                },
+                "sent": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "main.NewsStats": {
+            "type": "object",
+            "properties": {
+                "conversion": {
+                    "description": "Per language and notification channel, for the whole interval.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.NewsConversion"
+                    }
+                },
+                "newsId": {
+                    "type": "string",
+                    "example": "0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "series": {
+                    "description": "Daily, per language.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.NewsStatsEntry"
+                    }
+                }
+            }
+        },
+        "main.NewsStatsEntry": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string",
+                    "example": "2022-01-03T00:00:00Z"
+                },
+                "inAppOpens": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "inAppSent": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "newViewers": {
+                    "description": "The users that viewed it that day for the first time. Only the first view of each user is recorded.",
+                    "type": "integer",
+                    "example": 123
+                },
+                "organicOpens": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "pushOpens": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "pushSent": {
+                    "description": "How many ` + "`" + `news_added` + "`" + ` announcements were sent via each channel.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "main.PreviewPushNotificationTemplateRequestBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Optional. The values of the placeholders. Sample values are used, if not specified.",
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "main.ProcessEmailEventsRequestBody": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Required. Only permanent bounces and complaints suppress the email address.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.EmailEvent"
+                    }
+                }
+            }
+        },
+        "main.PublishPushNotificationTemplateRequestBody": {
+            "type": "object",
+            "properties": {
+                "version": {
+                    "description": "Required. Older versions can be published too, to roll back to them. ` + "`" + `0` + "`" + ` is the template the service is built with.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "main.ReorderFeaturedNewsRequestBody": {
+            "type": "object",
+            "properties": {
+                "newsIds": {
+                    "description": "Required. The featured news articles, in the order they're supposed to be shown. The ones not in it are shown after them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                    ]
+                }
+            }
+        },
+        "main.SendTestNotificationRequestBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Optional. The message the source of the notification type consumes, without the user ID.\nI.E. ` + "`" + `{\"type\":\"l2\",\"completedLevels\":2}` + "`" + ` for ` + "`" + `level_changed` + "`" + `, ` + "`" + `{\"type\":\"c1\",\"name\":\"Ice Breaker\"}` + "`" + ` for ` + "`" + `coin_badge_unlocked` + "`" + `,\n` + "`" + `{\"pingedBy\":\"\u003cuserId\u003e\"}` + "`" + ` for ` + "`" + `ping` + "`" + `, ` + "`" + `{\"contactUserId\":\"\u003cuserId\u003e\"}` + "`" + ` for ` + "`" + `new_contact` + "`" + `, ` + "`" + `{\"id\":\"\u003cuserId\u003e\",\"username\":\"jdoe\"}` + "`" + ` for ` + "`" + `new_referral` + "`" + `.",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "dryRun": {
+                    "description": "Optional. Set it to ` + "`" + `true` + "`" + ` to only render it, without sending it.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "userId": {
+                    "description": "Required. The user it's rendered for and, unless it's a ` + "`" + `dryRun` + "`" + `, sent to.",
+                    "type": "string",
+                    "example": "edfd8c02-75e0-4687-9ac2-1ce4723865c4"
+                }
+            }
+        },
+        "main.SetNewsReactionRequestBody": {
+            "type": "object",
+            "properties": {
+                "reaction": {
+                    "description": "Required. Either ` + "`" + `like` + "`" + ` or one of the configured emoji reactions.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/news.Reaction"
+                        }
+                    ],
+                    "example": "like"
+                }
+            }
+        },
+        "main.SetNotificationChannelTogglesRequestBody": {
+            "type": "object",
+            "properties": {
+                "toggles": {
+                    "description": "Required. The toggles that are not specified are left as they are.\nIf ` + "`" + `notificationTypes` + "`" + ` are specified, the ones that are not ` + "`" + `overridden` + "`" + ` follow their domain toggle again.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.NotificationChannelToggle"
+                    }
+                }
+            }
+        },
+        "main.ToggleNotificationChannelDomainRequestBody": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "main.ToggleNotificationChannelNotificationTypeRequestBody": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "news.ExperimentReport": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "newsId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "startedAt": {
+                    "description": "When the current variants were set. Only the views and reactions after it are counted. Empty if there are no variants.",
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/news.ExperimentVariantStats"
+                    }
+                },
+                "winner": {
+                    "description": "The variant with the most views(ties are broken by reactions). Empty if there's no data yet.",
+                    "type": "string",
+                    "example": "b"
+                }
+            }
+        },
+        "news.ExperimentVariantStats": {
+            "type": "object",
+            "properties": {
+                "imageUrl": {
+                    "type": "string",
+                    "example": "https://somewebsite.com/blockchain-b.jpg"
+                },
+                "reactions": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Why blockchain matters"
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "b"
+                },
+                "views": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "news.Reaction": {
+            "type": "string",
+            "enum": [
+                "like"
+            ],
+            "x-enum-varnames": [
+                "LikeReaction"
+            ]
+        },
+        "news.TaggedNews": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "draft": {
+                    "description": "Drafts are not visible to users and nobody is notified about them until they're published.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "imageUrl": {
+                    "type": "string",
+                    "example": "https://somewebsite.com/blockchain.jpg"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notificationChannels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "inapp",
+                            "sms",
+                            "email",
+                            "push",
+                            "analytics",
+                            "push||analytics",
+                            "push||email",
+                            "push||email||analytics"
+                        ]
+                    }
+                },
+                "pinnedUntil": {
+                    "description": "Only for ` + "`" + `featured` + "`" + ` news. Until then, it's shown before the other featured news, regardless of priority.",
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "priority": {
+                    "description": "Only for ` + "`" + `featured` + "`" + ` news. The ones with higher priorities are shown first.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "cats",
+                        "dogs",
+                        "frogs"
+                    ]
+                },
+                "targeting": {
+                    "description": "If set, only the users matching it are going to see it/get notified about it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.Targeting"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The importance of the blockchain technology"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/news.Type"
+                        }
+                    ],
+                    "example": "regular"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://somewebsite.com/blockchain"
+                },
+                "variants": {
+                    "description": "If set, users are split evenly and deterministically between the original title/image and each of these.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/news.Variant"
+                    }
+                },
+                "views": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "news.TranslationCoverage": {
+            "type": "object",
+            "properties": {
+                "existing": {
+                    "description": "The languages the news article is translated into, including the stale ones.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "de",
+                        "es"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "missing": {
+                    "description": "The expected languages the news article is not translated into yet.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     },
                     "example": [
-                        "cats",
-                        "dogs",
-                        "frogs"
+                        "fr"
+                    ]
+                },
+                "stale": {
+                    "description": "The translations that were updated before the latest ` + "`" + `en` + "`" + ` update.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "es"
                     ]
                 },
                 "title": {
@@ -662,35 +3075,111 @@ const docTemplate = `{
                 "updatedAt": {
                     "type": "string",
                     "example": "2022-01-03T16:20:52.156534Z"
+                }
+            }
+        },
+        "news.Type": {
+            "type": "string",
+            "enum": [
+                "regular",
+                "featured"
+            ],
+            "x-enum-varnames": [
+                "RegularNewsType",
+                "FeaturedNewsType"
+            ]
+        },
+        "news.Variant": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Has to be unique per news article and different from ` + "`" + `control` + "`" + `.",
+                    "type": "string",
+                    "example": "b"
                 },
-                "url": {
+                "imageUrl": {
+                    "description": "Optional. If empty, the original image is used.",
                     "type": "string",
-                    "example": "https://somewebsite.com/blockchain"
+                    "example": "https://somewebsite.com/blockchain-b.jpg"
                 },
-                "views": {
+                "title": {
+                    "description": "Optional. If empty, the original title is used.",
+                    "type": "string",
+                    "example": "Why blockchain matters"
+                }
+            }
+        },
+        "notifications.CapturedNotification": {
+            "type": "object",
+            "properties": {
+                "capturedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "id": {
                     "type": "integer",
-                    "example": 123
+                    "example": 1
+                },
+                "notificationChannel": {
+                    "description": "It's ` + "`" + `push` + "`" + `, ` + "`" + `email` + "`" + ` or ` + "`" + `inapp` + "`" + `.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.NotificationChannel"
+                        }
+                    ],
+                    "example": "push"
+                },
+                "payload": {
+                    "description": "The push notification, the email or the in-app parcel, exactly as it was handed over to be sent.",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "target": {
+                    "description": "The device token or the subscription topic, for ` + "`" + `push` + "`" + `; the email address, for ` + "`" + `email` + "`" + `; the user ID or the feed, for ` + "`" + `inapp` + "`" + `.",
+                    "type": "string",
+                    "example": "jdoe@example.com"
                 }
             }
         },
-        "main.ToggleNotificationChannelDomainRequestBody": {
+        "notifications.EmailEvent": {
             "type": "object",
             "properties": {
-                "enabled": {
+                "email": {
+                    "type": "string",
+                    "example": "jdoe@example.com"
+                },
+                "occurredAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "permanent": {
+                    "description": "Only for bounces. Temporary (soft) bounces are ignored.",
                     "type": "boolean",
                     "example": true
+                },
+                "type": {
+                    "enum": [
+                        "bounce",
+                        "complaint"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.EmailEventType"
+                        }
+                    ],
+                    "example": "bounce"
                 }
             }
         },
-        "news.Type": {
+        "notifications.EmailEventType": {
             "type": "string",
             "enum": [
-                "regular",
-                "featured"
+                "bounce",
+                "complaint"
             ],
             "x-enum-varnames": [
-                "RegularNewsType",
-                "FeaturedNewsType"
+                "BounceEmailEventType",
+                "ComplaintEmailEventType"
             ]
         },
         "notifications.InAppNotificationsUserAuthToken": {
@@ -707,6 +3196,361 @@ const docTemplate = `{
                 }
             }
         },
+        "notifications.NotificationChannel": {
+            "type": "string",
+            "enum": [
+                "inapp",
+                "sms",
+                "email",
+                "push",
+                "analytics",
+                "push||analytics",
+                "push||email",
+                "push||email||analytics"
+            ],
+            "x-enum-varnames": [
+                "InAppNotificationChannel",
+                "SMSNotificationChannel",
+                "EmailNotificationChannel",
+                "PushNotificationChannel",
+                "AnalyticsNotificationChannel",
+                "PushOrFallbackToAnalyticsNotificationChannel",
+                "PushOrFallbackToEmailNotificationChannel",
+                "PushOrFallbackToEmailOrFallbackToAnalyticsNotificationChannel"
+            ]
+        },
+        "notifications.NotificationChannelToggle": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "notificationTypes": {
+                    "description": "The notification types of the domain, which can be toggled individually, overriding the domain toggle.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.NotificationTypeToggle"
+                    }
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.NotificationDomain"
+                        }
+                    ],
+                    "example": "system"
+                }
+            }
+        },
+        "notifications.NotificationDomain": {
+            "type": "string",
+            "enum": [
+                "disable_all",
+                "all",
+                "weekly_report",
+                "weekly_stats",
+                "achievements",
+                "promotions",
+                "news",
+                "micro_community",
+                "mining",
+                "daily_bonus",
+                "system"
+            ],
+            "x-enum-varnames": [
+                "DisableAllNotificationDomain",
+                "AllNotificationDomain",
+                "WeeklyReportNotificationDomain",
+                "WeeklyStatsNotificationDomain",
+                "AchievementsNotificationDomain",
+                "PromotionsNotificationDomain",
+                "NewsNotificationDomain",
+                "MicroCommunityNotificationDomain",
+                "MiningNotificationDomain",
+                "DailyBonusNotificationDomain",
+                "SystemNotificationDomain"
+            ]
+        },
+        "notifications.NotificationType": {
+            "type": "string",
+            "enum": [
+                "adoption_changed",
+                "daily_bonus",
+                "new_contact",
+                "new_referral",
+                "news_added",
+                "ping",
+                "level_badge_unlocked",
+                "coin_badge_unlocked",
+                "social_badge_unlocked",
+                "role_changed",
+                "level_changed",
+                "mining_session_expiring",
+                "mining_session_expired",
+                "re_engagement_step_1",
+                "re_engagement_step_2",
+                "re_engagement_step_3"
+            ],
+            "x-enum-varnames": [
+                "AdoptionChangedNotificationType",
+                "DailyBonusNotificationType",
+                "NewContactNotificationType",
+                "NewReferralNotificationType",
+                "NewsAddedNotificationType",
+                "PingNotificationType",
+                "LevelBadgeUnlockedNotificationType",
+                "CoinBadgeUnlockedNotificationType",
+                "SocialBadgeUnlockedNotificationType",
+                "RoleChangedNotificationType",
+                "LevelChangedNotificationType",
+                "MiningSessionExpiringNotificationType",
+                "MiningSessionExpiredNotificationType",
+                "ReEngagementStep1NotificationType",
+                "ReEngagementStep2NotificationType",
+                "ReEngagementStep3NotificationType"
+            ]
+        },
+        "notifications.NotificationTypeToggle": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Either the override, if any, or the domain toggle.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "overridden": {
+                    "description": "Whether it's toggled individually, rather than inheriting the domain toggle.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.NotificationType"
+                        }
+                    ],
+                    "example": "ping"
+                }
+            }
+        },
+        "notifications.PushNotificationTemplate": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "ICU MessageFormat.",
+                    "type": "string",
+                    "example": "Start mining, to not lose your streak!"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "edfd8c02-75e0-4687-9ac2-1ce4723865c4"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notificationType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.NotificationType"
+                        }
+                    ],
+                    "example": "ping"
+                },
+                "published": {
+                    "description": "Whether it's the version that's used.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "publishedAt": {
+                    "description": "Only for the published version.",
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "title": {
+                    "description": "ICU MessageFormat.",
+                    "type": "string",
+                    "example": "{Username} pinged you"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "notifications.PushNotificationTemplatePreview": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Start mining, to not lose your streak!"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "title": {
+                    "type": "string",
+                    "example": "@jdoe pinged you"
+                }
+            }
+        },
+        "notifications.Targeting": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "description": "ISO 3166-1 alpha-2 country codes. The user's country has to be one of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "US",
+                        "RO"
+                    ]
+                },
+                "maxAppVersion": {
+                    "description": "Inclusive.",
+                    "type": "string",
+                    "example": "2.0.0"
+                },
+                "minAppVersion": {
+                    "description": "Inclusive.",
+                    "type": "string",
+                    "example": "1.2.0"
+                },
+                "segments": {
+                    "description": "The user has to be part of at least one of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "has_referrals",
+                            "new_user"
+                        ]
+                    }
+                }
+            }
+        },
+        "notifications.TestEmail": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "HTML, with the unsubscribe links.",
+                    "type": "string",
+                    "example": "\u003cp\u003eYour daily bonus is available\u003c/p\u003e"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "Your daily bonus is available"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "jdoe@example.com"
+                }
+            }
+        },
+        "notifications.TestNotification": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.TestEmail"
+                    }
+                },
+                "inApp": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inapp.Parcel"
+                    }
+                },
+                "push": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "body": {
+                                "type": "string"
+                            },
+                            "data": {
+                                "type": "object",
+                                "additionalProperties": {
+                                    "type": "string"
+                                }
+                            },
+                            "imageUrl": {
+                                "type": "string"
+                            },
+                            "target": {
+                                "type": "string"
+                            },
+                            "title": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "sent": {
+                    "description": "Whether it was sent to the user too, rather than just rendered.",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "notifications.UserPingResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "failed to send message to broker"
+                },
+                "lastPingCooldownEndedAt": {
+                    "description": "When the user can be pinged again. It's the previous one, if they weren't pinged.",
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "pinged": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "skipReason": {
+                    "enum": [
+                        "in_cooldown",
+                        "limit_reached",
+                        "concurrently_pinged"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.UserPingSkipReason"
+                        }
+                    ],
+                    "example": "in_cooldown"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "edfd8c02-75e0-4687-9ac2-1ce4723865c4"
+                }
+            }
+        },
+        "notifications.UserPingSkipReason": {
+            "type": "string",
+            "enum": [
+                "in_cooldown",
+                "limit_reached",
+                "concurrently_pinged"
+            ],
+            "x-enum-varnames": [
+                "InCooldownUserPingSkipReason",
+                "LimitReachedUserPingSkipReason",
+                "ConcurrentlyPingedUserPingSkipReason"
+            ]
+        },
         "server.ErrorResponse": {
             "type": "object",
             "properties": {
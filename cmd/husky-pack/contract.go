@@ -8,6 +8,7 @@ import (
 	"github.com/ice-blockchain/husky/analytics"
 	"github.com/ice-blockchain/husky/news"
 	"github.com/ice-blockchain/husky/notifications"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 // Public API.
@@ -20,9 +21,13 @@ type (
 		NewsImportFile *multipart.FileHeader `form:"newsImportFile" formMultipart:"newsImportFile" swaggerignore:"true"`
 		// Required, if `newsImportFile` param is not specified.
 		News string `form:"news" formMultipart:"news"`
+		// Optional. Set it to `true` to skip the near-duplicate title detection.
+		AllowSimilarTitles bool `form:"allowSimilarTitles" formMultipart:"allowSimilarTitles"`
 	}
 	ModifyNewsRequestBody struct {
 		MarkViewed *bool `form:"markViewed" formMultipart:"markViewed"`
+		// Optional. Only used with `markViewed`. It's the `contentSource` of the deeplink the user came from, if any.
+		Source news.ViewSource `form:"source" formMultipart:"source" swaggertype:"string" enums:"push,inapp,organic"`
 		// Optional.
 		Image *multipart.FileHeader `form:"image" formMultipart:"image" swaggerignore:"true"`
 		// Optional. Example: `financial`.
@@ -32,24 +37,171 @@ type (
 		// Optional.
 		Title string `form:"title" formMultipart:"title"`
 		// Optional. Example: `https://somewebsite.com/blockchain`.
-		URL      string `form:"url" formMultipart:"url"`
-		NewsID   string `uri:"newsId" swaggerignore:"true" required:"true" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
-		Language string `uri:"language" swaggerignore:"true" required:"true" example:"en"`
+		URL string `form:"url" formMultipart:"url"`
+		// Optional. Set it to `true` to skip the near-duplicate title detection.
+		AllowSimilarTitles bool   `form:"allowSimilarTitles" formMultipart:"allowSimilarTitles"`
+		NewsID             string `uri:"newsId" swaggerignore:"true" required:"true" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Language           string `uri:"language" swaggerignore:"true" required:"true" example:"en"`
 		// Optional. Setting this will save you from race conditions. Example:`1232412415326543647657`.
 		Checksum string `form:"checksum" formMultipart:"checksum"`
+		// Optional. JSON. Use `{}` to remove it. Example: `{"countries":["US","RO"],"segments":["has_referrals"],"minAppVersion":"1.2.0"}`.
+		Targeting       string `form:"targeting" formMultipart:"targeting"`
+		parsedTargeting *notifications.Targeting
+		// Optional. JSON. Use `[]` to stop the experiment. Example: `[{"id":"b","title":"Why blockchain matters"}]`.
+		Variants       string `form:"variants" formMultipart:"variants"`
+		parsedVariants *news.Variants
+		// Optional. Set it to `false` to publish a draft.
+		Draft *bool `form:"draft" formMultipart:"draft"`
+		// Optional. Only for `featured` news. The ones with higher priorities are shown first.
+		Priority *int64 `form:"priority" formMultipart:"priority"`
+		// Optional. Only for `featured` news. Use a date in the past to unpin it. Example: `2022-01-03T16:20:52.156534Z`.
+		PinnedUntil       string `form:"pinnedUntil" formMultipart:"pinnedUntil"`
+		parsedPinnedUntil *time.Time
+	}
+	GetNewsStatsArg struct {
+		NewsID string `uri:"newsId" swaggerignore:"true" required:"true" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		// Optional. Inclusive. Defaults to 30 days before `to`.
+		From string `form:"from" example:"2022-01-03T16:20:52.156534Z"`
+		// Optional. Exclusive. Defaults to now.
+		To string `form:"to" example:"2022-02-03T16:20:52.156534Z"`
+	}
+	NewsStats struct {
+		NewsID string `json:"newsId" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		// Daily, per language.
+		Series []*NewsStatsEntry `json:"series"`
+		// Per language and notification channel, for the whole interval.
+		Conversion []*NewsConversion `json:"conversion"`
+	}
+	NewsStatsEntry struct {
+		*news.DailyEngagement
+		// How many `news_added` announcements were sent via each channel.
+		PushSent  uint64 `json:"pushSent" example:"1"`
+		InAppSent uint64 `json:"inAppSent" example:"1"`
+	}
+	NewsConversion struct {
+		Language            string                            `json:"language" example:"en"`
+		NotificationChannel notifications.NotificationChannel `json:"notificationChannel" example:"push"`
+		Sent                uint64                            `json:"sent" example:"10"`
+		Opens               uint64                            `json:"opens" example:"5"`
+		// Opens/Sent. Push announcements broadcasted via topics are counted once per topic, so it's only meaningful for comparisons.
+		Rate float64 `json:"rate" example:"0.5"`
+	}
+	GetFeaturedNewsArg             struct{}
+	ReorderFeaturedNewsRequestBody struct {
+		// Required. The featured news articles, in the order they're supposed to be shown. The ones not in it are shown after them.
+		NewsIDs []string `json:"newsIds" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 	}
 	DeleteNewsArg struct {
 		NewsID   string `uri:"newsId" swaggerignore:"true" required:"true" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 		Language string `uri:"language" swaggerignore:"true" required:"true" example:"en"`
 	}
+	SetNewsReactionRequestBody struct {
+		// Required. Either `like` or one of the configured emoji reactions.
+		Reaction news.Reaction `json:"reaction" required:"true" example:"like"`
+		NewsID   string        `uri:"newsId" swaggerignore:"true" required:"true" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Language string        `uri:"language" swaggerignore:"true" required:"true" example:"en"`
+	}
+	GetNewsExperimentReportArg struct {
+		NewsID   string `uri:"newsId" swaggerignore:"true" required:"true" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Language string `uri:"language" swaggerignore:"true" required:"true" example:"en"`
+	}
+	GetNewsTranslationCoverageArg struct {
+		// Optional. If unspecified, every language that has at least one news article is expected.
+		Languages []string `form:"languages" example:"de,fr"`
+		Limit     uint64   `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset    uint64   `form:"offset" example:"5"`
+	}
+	RemoveNewsReactionArg struct {
+		NewsID   string `uri:"newsId" swaggerignore:"true" required:"true" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Language string `uri:"language" swaggerignore:"true" required:"true" example:"en"`
+	}
 	PingUserArg struct {
 		UserID string `uri:"userId" allowForbiddenWriteOperation:"true" required:"true" swaggerignore:"true" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
 	}
+	PingReferralsArg struct {
+		_ struct{} `allowForbiddenWriteOperation:"true"` //nolint:revive // It's processed by the router.
+	}
 	ToggleNotificationChannelDomainRequestBody struct {
 		Enabled             *bool                             `json:"enabled" required:"true" example:"true"`
 		Type                notifications.NotificationDomain  `uri:"type" example:"system"  swaggerignore:"true" required:"true" enums:"disable_all,weekly_report,weekly_stats,achievements,promotions,news,micro_community,mining,daily_bonus,system"` //nolint:lll // .
 		NotificationChannel notifications.NotificationChannel `uri:"notificationChannel" example:"push" swaggerignore:"true" enums:"push,email" required:"true"`
 	}
+	SetNotificationChannelTogglesRequestBody struct {
+		// Required. The toggles that are not specified are left as they are.
+		// If `notificationTypes` are specified, the ones that are not `overridden` follow their domain toggle again.
+		Toggles             []*notifications.NotificationChannelToggle `json:"toggles" required:"true"`
+		NotificationChannel notifications.NotificationChannel          `uri:"notificationChannel" example:"push" swaggerignore:"true" enums:"push,email" required:"true"` //nolint:lll // .
+	}
+	UnsubscribeFromEmailsArg struct {
+		_ struct{} `allowUnauthorized:"true"` //nolint:revive // It's processed by the router.
+		// Required. The token from the unsubscribe link.
+		Token string `form:"token" required:"true" example:"ZWRmZDhjMDJ-fn5kYWlseV9ib251c35-fjE3MDAwMDAwMDA.c2lnbmF0dXJl"`
+		// Optional. Set it to `true` to unsubscribe from all emails, not just the ones of the domain the link was generated for.
+		All bool `form:"all" example:"false"`
+	}
+	ProcessEmailEventsRequestBody struct {
+		_ struct{} `allowUnauthorized:"true"` //nolint:revive // It's processed by the router.
+		// Required. The configured webhook secret.
+		Secret string `header:"X-Webhook-Secret" swaggerignore:"true" required:"true"`
+		// Required. Only permanent bounces and complaints suppress the email address.
+		Events []*notifications.EmailEvent `json:"events" required:"true"`
+	}
+	ToggleNotificationChannelNotificationTypeRequestBody struct {
+		Enabled             *bool                             `json:"enabled" required:"true" example:"true"`
+		NotificationType    notifications.NotificationType    `uri:"notificationType" example:"ping" swaggerignore:"true" required:"true"`
+		NotificationChannel notifications.NotificationChannel `uri:"notificationChannel" example:"push" swaggerignore:"true" enums:"push,email" required:"true"`
+	}
+	ResetNotificationChannelNotificationTypeArg struct {
+		NotificationType    notifications.NotificationType    `uri:"notificationType" example:"ping" swaggerignore:"true" required:"true"`
+		NotificationChannel notifications.NotificationChannel `uri:"notificationChannel" example:"push" swaggerignore:"true" enums:"push,email" required:"true"`
+	}
+	GetPushNotificationTemplatesArg struct {
+		NotificationType notifications.NotificationType `uri:"notificationType" example:"ping" swaggerignore:"true" required:"true"`
+	}
+	GetPushNotificationTemplateVersionsArg struct {
+		NotificationType notifications.NotificationType `uri:"notificationType" example:"ping" swaggerignore:"true" required:"true"`
+		Language         string                         `uri:"language" example:"en" swaggerignore:"true" required:"true"`
+	}
+	CreatePushNotificationTemplateRequestBody struct {
+		// Required. ICU MessageFormat, with the same placeholders as the `en` template the service is built with.
+		Title string `json:"title" required:"true" example:"{Username} pinged you"`
+		// Required. ICU MessageFormat, with the same placeholders as the `en` template the service is built with.
+		Body             string                         `json:"body" required:"true" example:"Start mining, to not lose your streak!"`
+		NotificationType notifications.NotificationType `uri:"notificationType" example:"ping" swaggerignore:"true" required:"true"`
+		Language         string                         `uri:"language" example:"en" swaggerignore:"true" required:"true"`
+	}
+	PreviewPushNotificationTemplateRequestBody struct {
+		// Optional. The values of the placeholders. Sample values are used, if not specified.
+		Data             map[string]any                 `json:"data,omitempty"`
+		NotificationType notifications.NotificationType `uri:"notificationType" example:"ping" swaggerignore:"true" required:"true"`
+		Language         string                         `uri:"language" example:"en" swaggerignore:"true" required:"true"`
+		Version          uint64                         `uri:"version" example:"1" swaggerignore:"true"`
+	}
+	PublishPushNotificationTemplateRequestBody struct {
+		// Required. Older versions can be published too, to roll back to them. `0` is the template the service is built with.
+		Version          *uint64                        `json:"version" required:"true" example:"1"`
+		NotificationType notifications.NotificationType `uri:"notificationType" example:"ping" swaggerignore:"true" required:"true"`
+		Language         string                         `uri:"language" example:"en" swaggerignore:"true" required:"true"`
+	}
+	SendTestNotificationRequestBody struct {
+		// Optional. The message the source of the notification type consumes, without the user ID.
+		// I.E. `{"type":"l2","completedLevels":2}` for `level_changed`, `{"type":"c1","name":"Ice Breaker"}` for `coin_badge_unlocked`,
+		// `{"pingedBy":"<userId>"}` for `ping`, `{"contactUserId":"<userId>"}` for `new_contact`, `{"id":"<userId>","username":"jdoe"}` for `new_referral`.
+		Data map[string]any `json:"data,omitempty"`
+		// Required. The user it's rendered for and, unless it's a `dryRun`, sent to.
+		UserID           string                         `json:"userId" required:"true" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
+		NotificationType notifications.NotificationType `uri:"notificationType" example:"level_changed" swaggerignore:"true" required:"true"`
+		// Optional. Set it to `true` to only render it, without sending it.
+		DryRun bool `json:"dryRun,omitempty" example:"true"`
+	}
+	GetCapturedNotificationsArg struct {
+		// Optional. Example: any of `push`, `email`, `inapp`.
+		NotificationChannel notifications.NotificationChannel `form:"notificationChannel" example:"push"`
+		// Optional. The device token or the subscription topic, the email address, the user ID or the in-app feed.
+		Target string `form:"target" example:"jdoe@example.com"`
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
 	News struct {
 		*news.TaggedNews
 		Checksum string `json:"checksum,omitempty" example:"1232412415326543647657"`
@@ -264,9 +416,11 @@ const (
 	alreadyViewedNewsErrorCode = "ALREADY_VIEWED_NEWS"
 	raceConditionErrorCode     = "RACE_CONDITION"
 	newsNotFoundErrorCode      = "NEWS_NOT_FOUND"
+	templateNotFoundErrorCode  = "TEMPLATE_NOT_FOUND"
 	userNotFoundErrorCode      = "USER_NOT_FOUND"
 	userAlreadyPingedErrorCode = "USER_ALREADY_PINGED"
 	invalidPropertiesErrorCode = "INVALID_PROPERTIES"
+	invalidTokenErrorCode      = "INVALID_TOKEN"
 )
 
 type (
@@ -38,6 +38,7 @@ func main() {
 
 func (s *service) RegisterRoutes(router *server.Router) {
 	s.setupNotificationsRoutes(router)
+	s.setupPushNotificationTemplatesRoutes(router)
 	s.setupNewsRoutes(router)
 }
 
@@ -20,6 +20,7 @@ import (
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/server"
 	"github.com/ice-blockchain/wintr/terror"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 func (s *service) setupNewsRoutes(router *server.Router) {
@@ -27,7 +28,15 @@ func (s *service) setupNewsRoutes(router *server.Router) {
 		Group("v1w").
 		POST("news", server.RootHandler(s.CreateNews)).
 		DELETE("news/:language/:newsId", server.RootHandler(s.DeleteNews)).
-		PATCH("news/:language/:newsId", server.RootHandler(s.ModifyNews))
+		PATCH("news/:language/:newsId", server.RootHandler(s.ModifyNews)).
+		GET("news/:language/:newsId/experiment-report", server.RootHandler(s.GetNewsExperimentReport)).
+		GET("news/translation-coverage", server.RootHandler(s.GetNewsTranslationCoverage)).
+		GET("news/featured", server.RootHandler(s.GetFeaturedNews)).
+		PUT("news/featured", server.RootHandler(s.ReorderFeaturedNews)).
+		GET("news/stats/:newsId", server.RootHandler(s.GetNewsStats)).
+		GET("news/stats/:newsId/csv", s.ExportNewsStats).
+		PUT("news/:language/:newsId/reactions", server.RootHandler(s.SetNewsReaction)).
+		DELETE("news/:language/:newsId/reactions", server.RootHandler(s.RemoveNewsReaction))
 }
 
 // CreateNews godoc
@@ -45,7 +54,7 @@ func (s *service) setupNewsRoutes(router *server.Router) {
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		403					{object}	server.ErrorResponse	"not allowed"
-//	@Failure		409					{object}	server.ErrorResponse	"if it conflicts with existing news articles"
+//	@Failure		409					{object}	server.ErrorResponse	"if it conflicts with existing news articles(same url or a similar title); the conflicting article is in `data.news`"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500					{object}	server.ErrorResponse
 //	@Failure		504					{object}	server.ErrorResponse	"if request times out"
@@ -64,13 +73,11 @@ func (s *service) CreateNews( //nolint:gocritic,funlen // .
 	if err = req.Data.validateNews(inputNews); err != nil {
 		return nil, server.BadRequest(errors.Wrapf(err, "invalid news records"), invalidPropertiesErrorCode)
 	}
-	if err = s.newsProcessor.CreateNews(ctx, inputNews, req.Data.Image); err != nil {
+	if err = s.newsProcessor.CreateNews(news.ContextWithSimilarTitlesAllowed(ctx, req.Data.AllowSimilarTitles), inputNews, req.Data.Image); err != nil {
 		err = errors.Wrapf(err, "failed to create news for %#v", inputNews)
 		switch {
 		case errors.Is(err, news.ErrDuplicate):
-			if tErr := terror.As(err); tErr != nil {
-				return nil, server.Conflict(err, duplicateNewsErrorCode, tErr.Data)
-			}
+			return nil, duplicateNewsConflict(err)
 		default:
 			return nil, server.Unexpected(err)
 		}
@@ -180,6 +187,12 @@ func (*CreateNewsRequestBody) validateNews(nws []*news.TaggedNews) error {
 		if nw.Title == "" {
 			errChan <- fmt.Sprintf("missing `[%v].title`", idx)
 		}
+		if err := nw.Targeting.Validate(); err != nil {
+			errChan <- fmt.Sprintf("invalid `[%v].targeting`, %v", idx, err)
+		}
+		if err := validateVariants(nw.Variants); err != nil {
+			errChan <- fmt.Sprintf("invalid `[%v].variants`, %v", idx, err)
+		}
 		go func(ix int) {
 			defer wg.Done()
 			if nws[ix].URL == "" {
@@ -276,7 +289,7 @@ func (s *service) DeleteNews( //nolint:gocritic // .
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
 //	@Failure		404					{object}	server.ErrorResponse	"if news or user not found"
-//	@Failure		409					{object}	server.ErrorResponse	"if conflict occurs"
+//	@Failure		409					{object}	server.ErrorResponse	"if conflict occurs(same url or a similar title); the conflicting article is in `data.news`"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500					{object}	server.ErrorResponse
 //	@Failure		504					{object}	server.ErrorResponse	"if request times out"
@@ -286,7 +299,10 @@ func (s *service) ModifyNews( //nolint:gocritic,funlen // .
 	req *server.Request[ModifyNewsRequestBody, News],
 ) (*server.Response[News], *server.Response[server.ErrorResponse]) {
 	if req.Data.MarkViewed != nil && *req.Data.MarkViewed {
-		if err := s.newsProcessor.IncrementViews(ctx, req.Data.NewsID, req.Data.Language); err != nil {
+		if err := validateViewSource(req.Data.Source); err != nil {
+			return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+		}
+		if err := s.newsProcessor.IncrementViews(news.ContextWithViewSource(ctx, req.Data.Source), req.Data.NewsID, req.Data.Language); err != nil {
 			err = errors.Wrapf(err, "failed to increment views for %#v", req.Data)
 			switch {
 			case errors.Is(err, storage.ErrNotFound):
@@ -308,15 +324,21 @@ func (s *service) ModifyNews( //nolint:gocritic,funlen // .
 	}
 	nws := &news.TaggedNews{
 		News: &news.News{
-			ID:       req.Data.NewsID,
-			Language: req.Data.Language,
-			Type:     req.Data.Type,
-			Title:    req.Data.Title,
-			URL:      req.Data.URL,
+			ID:          req.Data.NewsID,
+			Language:    req.Data.Language,
+			Type:        req.Data.Type,
+			Title:       req.Data.Title,
+			URL:         req.Data.URL,
+			Targeting:   req.Data.parsedTargeting,
+			Variants:    req.Data.parsedVariants,
+			Draft:       req.Data.Draft,
+			Priority:    req.Data.Priority,
+			PinnedUntil: req.Data.parsedPinnedUntil,
 		},
 		Tags: req.Data.Tags,
 	}
-	if err := s.newsProcessor.ModifyNews(news.ContextWithChecksum(ctx, req.Data.Checksum), nws, req.Data.Image); err != nil {
+	modifyCtx := news.ContextWithSimilarTitlesAllowed(news.ContextWithChecksum(ctx, req.Data.Checksum), req.Data.AllowSimilarTitles)
+	if err := s.newsProcessor.ModifyNews(modifyCtx, nws, req.Data.Image); err != nil {
 		err = errors.Wrapf(err, "failed to modify news for %#v", req.Data)
 		switch {
 		case errors.Is(err, news.ErrInvalidImageExtension):
@@ -326,7 +348,7 @@ func (s *service) ModifyNews( //nolint:gocritic,funlen // .
 		case errors.Is(err, news.ErrNotFound):
 			return nil, server.NotFound(err, newsNotFoundErrorCode)
 		case errors.Is(err, news.ErrDuplicate):
-			return nil, server.Conflict(err, duplicateNewsErrorCode)
+			return nil, duplicateNewsConflict(err)
 		default:
 			return nil, server.Unexpected(err)
 		}
@@ -335,6 +357,15 @@ func (s *service) ModifyNews( //nolint:gocritic,funlen // .
 	return server.OK(&News{TaggedNews: nws, Checksum: nws.Checksum()}), nil
 }
 
+// duplicateNewsConflict includes the conflicting `field` and, if known, the conflicting `news` article, in the response data.
+func duplicateNewsConflict(err error) *server.Response[server.ErrorResponse] {
+	if tErr := terror.As(err); tErr != nil {
+		return server.Conflict(err, duplicateNewsErrorCode, tErr.Data)
+	}
+
+	return server.Conflict(err, duplicateNewsErrorCode)
+}
+
 func (req *ModifyNewsRequestBody) validate() error {
 	var errs []string
 	if req.Type != "" && req.Type != news.FeaturedNewsType && req.Type != news.RegularNewsType {
@@ -343,7 +374,30 @@ func (req *ModifyNewsRequestBody) validate() error {
 	if err := validateURL(req.URL); err != nil {
 		errs = append(errs, fmt.Sprintf("invalid `url=%q`, %v", req.URL, err))
 	}
-	if req.Type == "" && req.Image == nil && req.Title == "" && req.Tags == nil && req.URL == "" {
+	if req.Targeting != "" {
+		req.parsedTargeting = new(notifications.Targeting)
+		if err := json.Unmarshal([]byte(req.Targeting), req.parsedTargeting); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid `targeting=%q`, %v", req.Targeting, err))
+		} else if err = req.parsedTargeting.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid `targeting=%q`, %v", req.Targeting, err))
+		}
+	}
+	if req.Variants != "" {
+		req.parsedVariants = new(news.Variants)
+		if err := json.Unmarshal([]byte(req.Variants), req.parsedVariants); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid `variants=%q`, %v", req.Variants, err))
+		} else if err = validateVariants(req.parsedVariants); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid `variants=%q`, %v", req.Variants, err))
+		}
+	}
+	if req.PinnedUntil != "" {
+		req.parsedPinnedUntil = new(time.Time)
+		if err := req.parsedPinnedUntil.UnmarshalText([]byte(req.PinnedUntil)); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid `pinnedUntil=%q`, %v", req.PinnedUntil, err))
+		}
+	}
+	if req.Type == "" && req.Image == nil && req.Title == "" && req.Tags == nil && req.URL == "" && req.Targeting == "" && req.Variants == "" && req.Draft == nil &&
+		req.Priority == nil && req.PinnedUntil == "" {
 		errs = append(errs, "at least one property has to be specified")
 	}
 	if len(errs) != 0 {
@@ -353,6 +407,270 @@ func (req *ModifyNewsRequestBody) validate() error {
 	return nil
 }
 
+// SetNewsReaction godoc
+//
+//	@Schemes
+//	@Description	Sets the authorized user's reaction to a language variant of a news article, replacing the previous one, if any.
+//	@Tags			News
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header	string						true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			newsId			path	string						true	"ID of the news article"
+//	@Param			language		path	string						true	"The language of the news article"
+//	@Param			request			body	SetNewsReactionRequestBody	true	"Request params"
+//	@Success		200				"ok"
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404				{object}	server.ErrorResponse	"if news not found"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/{language}/{newsId}/reactions [PUT].
+func (s *service) SetNewsReaction( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[SetNewsReactionRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.newsProcessor.AddReaction(ctx, req.Data.NewsID, req.Data.Language, req.Data.Reaction); err != nil {
+		err = errors.Wrapf(err, "failed to add news reaction for %#v", req.Data)
+		switch {
+		case errors.Is(err, news.ErrInvalidReaction):
+			return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+		case errors.Is(err, news.ErrNotFound):
+			return nil, server.NotFound(err, newsNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// RemoveNewsReaction godoc
+//
+//	@Schemes
+//	@Description	Removes the authorized user's reaction from a language variant of a news article.
+//	@Tags			News
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header	string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			newsId			path	string	true	"ID of the news article"
+//	@Param			language		path	string	true	"The language of the news article"
+//	@Success		200				"OK - found and deleted"
+//	@Success		204				"No Content - already deleted"
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/{language}/{newsId}/reactions [DELETE].
+func (s *service) RemoveNewsReaction( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[RemoveNewsReactionArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.newsProcessor.RemoveReaction(ctx, req.Data.NewsID, req.Data.Language); err != nil {
+		err = errors.Wrapf(err, "failed to remove news reaction for %#v", req.Data)
+		switch {
+		case errors.Is(err, news.ErrNotFound):
+			return server.NoContent(), nil
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// GetNewsExperimentReport godoc
+//
+//	@Schemes
+//	@Description	Returns the views and reactions of each title/image variant of a language variant of a news article, and which one is winning.
+//	@Tags			News
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			newsId			path		string	true	"ID of the news article"
+//	@Param			language		path		string	true	"The language of the news article"
+//	@Success		200				{object}	news.ExperimentReport
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404				{object}	server.ErrorResponse	"if news not found"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/{language}/{newsId}/experiment-report [GET].
+func (s *service) GetNewsExperimentReport( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetNewsExperimentReportArg, news.ExperimentReport],
+) (*server.Response[news.ExperimentReport], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAuthorizedToAlterNews(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	resp, err := s.newsProcessor.GetExperimentReport(ctx, req.Data.NewsID, req.Data.Language)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get experiment report for %#v", req.Data)
+		switch {
+		case errors.Is(err, news.ErrNotFound):
+			return nil, server.NotFound(err, newsNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(resp), nil
+}
+
+// GetNewsTranslationCoverage godoc
+//
+//	@Schemes
+//	@Description	Returns, for each `en` news article, the languages it is translated into, the translations that are stale and the ones that are missing.
+//	@Tags			News
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"Insert your access token"																					default(Bearer <Add access token here>)
+//	@Param			languages		query		[]string	false	"The expected languages. If unspecified, every language that has at least one news article is expected."	collectionFormat(multi)
+//	@Param			limit			query		uint64		false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset			query		uint64		false	"Elements to skip before starting to look for"
+//	@Success		200				{array}		news.TranslationCoverage
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/translation-coverage [GET].
+func (s *service) GetNewsTranslationCoverage( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetNewsTranslationCoverageArg, []*news.TranslationCoverage],
+) (*server.Response[[]*news.TranslationCoverage], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAuthorizedToAlterNews(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = 10
+	}
+	if req.Data.Limit > 1000 { //nolint:gomnd //.
+		req.Data.Limit = 1000
+	}
+	for ix, language := range req.Data.Languages {
+		req.Data.Languages[ix] = strings.ToLower(language)
+		if _, found := languages[req.Data.Languages[ix]]; !found {
+			return nil, server.BadRequest(errors.Errorf("invalid language `%v`", language), invalidPropertiesErrorCode)
+		}
+	}
+	resp, err := s.newsProcessor.GetTranslationCoverage(ctx, req.Data.Languages, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get translation coverage for %#v", req.Data))
+	}
+
+	return server.OK(&resp), nil
+}
+
+// GetFeaturedNews godoc
+//
+//	@Schemes
+//	@Description	Returns the published, untargeted featured `en` news articles, in the order they're shown to the users.
+//	@Tags			News
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Success		200				{array}		news.TaggedNews
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/featured [GET].
+func (s *service) GetFeaturedNews( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetFeaturedNewsArg, []*news.TaggedNews],
+) (*server.Response[[]*news.TaggedNews], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAuthorizedToAlterNews(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	resp, err := s.newsProcessor.GetFeaturedNews(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get featured news"))
+	}
+
+	return server.OK(&resp), nil
+}
+
+// ReorderFeaturedNews godoc
+//
+//	@Schemes
+//	@Description	Reorders the featured news articles. The ones pinned are still shown first, until their pin expires.
+//	@Tags			News
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string							true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			request			body		ReorderFeaturedNewsRequestBody	true	"Request params"
+//	@Success		200				{array}		news.TaggedNews
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404				{object}	server.ErrorResponse	"if at least one of the news is not a published, untargeted featured news article"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/featured [PUT].
+func (s *service) ReorderFeaturedNews( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[ReorderFeaturedNewsRequestBody, []*news.TaggedNews],
+) (*server.Response[[]*news.TaggedNews], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAuthorizedToAlterNews(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	ids := make(map[string]struct{}, len(req.Data.NewsIDs))
+	for ix, id := range req.Data.NewsIDs {
+		if _, duplicate := ids[id]; duplicate || id == "" {
+			return nil, server.BadRequest(errors.Errorf("invalid `newsIds[%v]=%q`", ix, id), invalidPropertiesErrorCode)
+		}
+		ids[id] = struct{}{}
+	}
+	if err := s.newsProcessor.ReorderFeaturedNews(ctx, req.Data.NewsIDs); err != nil {
+		err = errors.Wrapf(err, "failed to reorder featured news for %#v", req.Data)
+		if errors.Is(err, news.ErrNotFound) {
+			return nil, server.NotFound(err, newsNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+	resp, err := s.newsProcessor.GetFeaturedNews(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get featured news"))
+	}
+
+	return server.OK(&resp), nil
+}
+
+func validateVariants(variants *news.Variants) error {
+	if variants == nil {
+		return nil
+	}
+	ids := make(map[string]struct{}, len(*variants))
+	for ix, variant := range *variants {
+		if variant == nil || variant.ID == "" {
+			return errors.Errorf("missing `[%v].id`", ix)
+		}
+		if variant.ID == news.ControlVariant {
+			return errors.Errorf("`[%v].id=%q` is reserved for the original", ix, variant.ID)
+		}
+		if _, duplicate := ids[variant.ID]; duplicate {
+			return errors.Errorf("`[%v].id=%q` is present in multiple items", ix, variant.ID)
+		}
+		ids[variant.ID] = struct{}{}
+		if variant.Title == "" && variant.ImageURL == "" {
+			return errors.Errorf("at least one of `[%v].title` or `[%v].imageUrl` has to be specified", ix, ix)
+		}
+		if err := validateURL(variant.ImageURL); err != nil {
+			return errors.Wrapf(err, "invalid `[%v].imageUrl=%q`", ix, variant.ImageURL)
+		}
+	}
+
+	return nil
+}
+
 func verifyIfAuthorizedToAlterNews(usr *server.AuthenticatedUser) error {
 	if !strings.EqualFold(usr.Role, "admin") && !strings.EqualFold(usr.Role, "author") {
 		return errors.Errorf("access denied, invalid role `%v`", usr.Role)
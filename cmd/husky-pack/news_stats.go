@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/husky/news"
+	"github.com/ice-blockchain/husky/notifications"
+	"github.com/ice-blockchain/wintr/auth"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	defaultNewsStatsInterval = 30 * 24 * stdlibtime.Hour
+	exportNewsStatsTimeout   = 30 * stdlibtime.Second
+	newsStatsDayFormat       = "2006-01-02"
+)
+
+// GetNewsStats godoc
+//
+//	@Schemes
+//	@Description	Returns the daily engagement with a news article, per language: new viewers, opens by source and `news_added` announcements sent.
+//	@Tags			News
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			newsId			path		string	true	"ID of the news article"
+//	@Param			from			query		string	false	"Inclusive. Defaults to 30 days before `to`. Example `2022-01-03T16:20:52.156534Z`."
+//	@Param			to				query		string	false	"Exclusive. Defaults to now. Example `2022-02-03T16:20:52.156534Z`."
+//	@Success		200				{object}	NewsStats
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/stats/{newsId} [GET].
+func (s *service) GetNewsStats( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetNewsStatsArg, NewsStats],
+) (*server.Response[NewsStats], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAuthorizedToAlterNews(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	stats, failure := s.newsStats(ctx, req.Data)
+	if failure != nil {
+		return nil, failure
+	}
+
+	return server.OK(stats), nil
+}
+
+// ExportNewsStats godoc
+//
+//	@Schemes
+//	@Description	Same as `GET /news/stats/{newsId}`, but it returns the daily series as CSV.
+//	@Tags			News
+//	@Produce		text/csv
+//	@Param			Authorization	header		string					true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			newsId			path		string					true	"ID of the news article"
+//	@Param			from			query		string					false	"Inclusive. Defaults to 30 days before `to`. Example `2022-01-03T16:20:52.156534Z`."
+//	@Param			to				query		string					false	"Exclusive. Defaults to now. Example `2022-02-03T16:20:52.156534Z`."
+//	@Success		200				{string}	string					"the CSV"
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/stats/{newsId}/csv [GET].
+func (s *service) ExportNewsStats(ginCtx *gin.Context) {
+	ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), exportNewsStatsTimeout)
+	defer cancel()
+	usr, failure := authenticate(ctx, ginCtx)
+	if failure != nil {
+		writeFailure(ginCtx, failure)
+
+		return
+	}
+	if err := verifyIfAuthorizedToAlterNews(usr); err != nil {
+		writeFailure(ginCtx, server.Forbidden(err))
+
+		return
+	}
+	arg := &GetNewsStatsArg{NewsID: ginCtx.Param("newsId")}
+	if err := ginCtx.ShouldBindQuery(arg); err != nil {
+		writeFailure(ginCtx, server.UnprocessableEntity(errors.Wrap(err, "failed to bind query"), invalidPropertiesErrorCode))
+
+		return
+	}
+	stats, failure := s.newsStats(ctx, arg)
+	if failure != nil {
+		writeFailure(ginCtx, failure)
+
+		return
+	}
+	content, err := stats.csv()
+	if err != nil {
+		writeFailure(ginCtx, server.Unexpected(errors.Wrapf(err, "failed to convert %#v to csv", stats)))
+
+		return
+	}
+	ginCtx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="news-stats-%v.csv"`, stats.NewsID))
+	ginCtx.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+func (s *service) newsStats(ctx context.Context, arg *GetNewsStatsArg) (*NewsStats, *server.Response[server.ErrorResponse]) {
+	from, to, err := arg.interval()
+	if err != nil {
+		return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+	}
+	engagement, err := s.newsProcessor.GetEngagement(ctx, arg.NewsID, from, to)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get engagement for %#v", arg))
+	}
+	announcements, err := s.notificationsProcessor.GetAnnouncementStats(ctx, notifications.NewsAddedNotificationType, arg.NewsID, from, to)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get announcement stats for %#v", arg))
+	}
+
+	return newNewsStats(arg.NewsID, engagement, announcements), nil
+}
+
+// authenticate does what server.RootHandler does, for the handlers that don't respond with JSON.
+func authenticate(ctx context.Context, ginCtx *gin.Context) (*server.AuthenticatedUser, *server.Response[server.ErrorResponse]) {
+	token, err := server.Auth(ctx).VerifyToken(ctx, strings.TrimPrefix(ginCtx.GetHeader("Authorization"), "Bearer "))
+	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			return nil, server.Forbidden(err)
+		}
+
+		return nil, server.Unauthorized(err)
+	}
+	if token, err = server.Auth(ctx).ModifyTokenWithMetadata(token, ginCtx.GetHeader("X-Account-Metadata")); err != nil {
+		return nil, server.Unauthorized(err)
+	}
+
+	return &server.AuthenticatedUser{Token: *token}, nil
+}
+
+func writeFailure(ginCtx *gin.Context, failure *server.Response[server.ErrorResponse]) {
+	log.Error(errors.Wrap(failure.Data.InternalErr(), "endpoint failed"), "Response", failure)
+	if failure.Code <= 0 {
+		ginCtx.JSON(http.StatusInternalServerError, server.ErrorResponse{Error: "oops, something went wrong"})
+
+		return
+	}
+	ginCtx.JSON(failure.Code, failure.Data)
+}
+
+func (arg *GetNewsStatsArg) interval() (from, to *time.Time, err error) {
+	to = time.Now()
+	if arg.To != "" {
+		to = new(time.Time)
+		if err = to.UnmarshalText([]byte(arg.To)); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid `to=%q`", arg.To)
+		}
+	}
+	from = time.New(to.Add(-defaultNewsStatsInterval))
+	if arg.From != "" {
+		from = new(time.Time)
+		if err = from.UnmarshalText([]byte(arg.From)); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid `from=%q`", arg.From)
+		}
+	}
+	if !from.Before(*to.Time) {
+		return nil, nil, errors.Errorf("`from=%v` has to be before `to=%v`", from, to)
+	}
+
+	return from, to, nil
+}
+
+func newNewsStats(newsID string, engagement []*news.DailyEngagement, announcements []*notifications.AnnouncementStats) *NewsStats {
+	type (
+		seriesKey struct {
+			Day, Language string
+		}
+		conversionKey struct {
+			Language            string
+			NotificationChannel notifications.NotificationChannel
+		}
+	)
+	stats := &NewsStats{NewsID: newsID, Series: make([]*NewsStatsEntry, 0, len(engagement)), Conversion: make([]*NewsConversion, 0)}
+	series := make(map[seriesKey]*NewsStatsEntry, len(engagement))
+	for _, daily := range engagement {
+		entry := &NewsStatsEntry{DailyEngagement: daily}
+		series[seriesKey{Day: daily.Day.Format(newsStatsDayFormat), Language: daily.Language}] = entry
+		stats.Series = append(stats.Series, entry)
+	}
+	conversions := make(map[conversionKey]*NewsConversion)
+	conversion := func(language string, channel notifications.NotificationChannel) *NewsConversion {
+		key := conversionKey{Language: language, NotificationChannel: channel}
+		if _, found := conversions[key]; !found {
+			conversions[key] = &NewsConversion{Language: language, NotificationChannel: channel}
+			stats.Conversion = append(stats.Conversion, conversions[key])
+		}
+
+		return conversions[key]
+	}
+	for _, announcement := range announcements {
+		key := seriesKey{Day: announcement.Day.Format(newsStatsDayFormat), Language: announcement.Language}
+		entry, found := series[key]
+		if !found {
+			entry = &NewsStatsEntry{DailyEngagement: &news.DailyEngagement{Day: announcement.Day, Language: announcement.Language}}
+			series[key] = entry
+			stats.Series = append(stats.Series, entry)
+		}
+		switch announcement.NotificationChannel { //nolint:exhaustive // Only these are used for news.
+		case notifications.PushNotificationChannel:
+			entry.PushSent += announcement.Sent
+		case notifications.InAppNotificationChannel:
+			entry.InAppSent += announcement.Sent
+		}
+		conversion(announcement.Language, announcement.NotificationChannel).Sent += announcement.Sent
+	}
+	for _, daily := range engagement {
+		conversion(daily.Language, notifications.PushNotificationChannel).Opens += daily.PushOpens
+		conversion(daily.Language, notifications.InAppNotificationChannel).Opens += daily.InAppOpens
+	}
+	relevantConversions := stats.Conversion[:0]
+	for _, conv := range stats.Conversion {
+		if conv.Sent == 0 && conv.Opens == 0 {
+			continue
+		}
+		if conv.Sent != 0 {
+			conv.Rate = float64(conv.Opens) / float64(conv.Sent)
+		}
+		relevantConversions = append(relevantConversions, conv)
+	}
+	stats.Conversion = relevantConversions
+	sortNewsStatsSeries(stats.Series)
+
+	return stats
+}
+
+func sortNewsStatsSeries(series []*NewsStatsEntry) {
+	sort.SliceStable(series, func(i, j int) bool {
+		if !series[i].Day.Equal(*series[j].Day.Time) {
+			return series[i].Day.Before(*series[j].Day.Time)
+		}
+
+		return series[i].Language < series[j].Language
+	})
+}
+
+func (ns *NewsStats) csv() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	writer := csv.NewWriter(buf)
+	records := make([][]string, 0, 1+len(ns.Series))
+	records = append(records, []string{
+		"day", "language", "newViewers", "pushOpens", "inAppOpens", "organicOpens", "pushSent", "inAppSent",
+	})
+	for _, entry := range ns.Series {
+		records = append(records, []string{
+			entry.Day.Format(newsStatsDayFormat),
+			entry.Language,
+			strconv.FormatUint(entry.NewViewers, 10),
+			strconv.FormatUint(entry.PushOpens, 10),
+			strconv.FormatUint(entry.InAppOpens, 10),
+			strconv.FormatUint(entry.OrganicOpens, 10),
+			strconv.FormatUint(entry.PushSent, 10),
+			strconv.FormatUint(entry.InAppSent, 10),
+		})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return nil, errors.Wrap(err, "failed to write csv records")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func validateViewSource(source news.ViewSource) error {
+	if source == "" {
+		return nil
+	}
+	for _, validSource := range news.AllViewSources {
+		if validSource == source {
+			return nil
+		}
+	}
+
+	return errors.Errorf("invalid `source=%q`. Allowed: %#v", source, news.AllViewSources)
+}
@@ -4,8 +4,14 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"html"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/husky/notifications"
@@ -16,8 +22,17 @@ func (s *service) setupNotificationsRoutes(router *server.Router) {
 	router.
 		Group("v1w").
 		POST("user-pings/:userId", server.RootHandler(s.PingUser)).
+		POST("user-pings", server.RootHandler(s.PingReferrals)).
+		PUT("notification-channels/:notificationChannel/toggles", server.RootHandler(s.SetNotificationChannelToggles)).
 		PUT("notification-channels/:notificationChannel/toggles/:type", server.RootHandler(s.ToggleNotificationChannelDomain)).
-		PUT("inapp-notifications-user-auth-token", server.RootHandler(s.GenerateInAppNotificationsUserAuthToken))
+		PUT("notification-channels/:notificationChannel/notification-types/:notificationType", server.RootHandler(s.ToggleNotificationChannelNotificationType)).
+		DELETE("notification-channels/:notificationChannel/notification-types/:notificationType", server.RootHandler(s.ResetNotificationChannelNotificationType)).
+		PUT("inapp-notifications-user-auth-token", server.RootHandler(s.GenerateInAppNotificationsUserAuthToken)).
+		GET("email-unsubscriptions", s.ConfirmEmailUnsubscription).
+		POST("email-unsubscriptions", server.RootHandler(s.UnsubscribeFromEmails)).
+		POST("email-events", server.RootHandler(s.ProcessEmailEvents)).
+		POST("test-notifications/:notificationType", server.RootHandler(s.SendTestNotification)).
+		GET("captured-notifications", server.RootHandler(s.GetCapturedNotifications))
 }
 
 // PingUser godoc
@@ -60,6 +75,32 @@ func (s *service) PingUser( //nolint:gocritic // .
 	return &server.Response[any]{Code: http.StatusAccepted}, nil
 }
 
+// PingReferrals godoc
+//
+//	@Schemes
+//	@Description	Pings all the referrals of the user that can be pinged, I.E. the ones whose ping cooldown ended. At most 1000 are pinged at once. Up to 1000 of the skipped ones are returned as well, with the `skipReason`.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Success		200				{array}		notifications.UserPingResult
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/user-pings [POST].
+func (s *service) PingReferrals( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[PingReferralsArg, []*notifications.UserPingResult],
+) (*server.Response[[]*notifications.UserPingResult], *server.Response[server.ErrorResponse]) {
+	results, err := s.notificationsProcessor.PingReferrals(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to ping the referrals of userID:%v", req.AuthenticatedUser.UserID))
+	}
+
+	return server.OK(&results), nil
+}
+
 // ToggleNotificationChannelDomain godoc
 //
 //	@Schemes
@@ -113,6 +154,348 @@ func (arg *ToggleNotificationChannelDomainRequestBody) validate() error {
 	return errors.Errorf("invalid type `%v`", arg.Type)
 }
 
+// SetNotificationChannelToggles godoc
+//
+//	@Schemes
+//	@Description	Sets multiple notification channel toggles at once, atomically, and returns the resulting state of all of them, `disable_all` included.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string										true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			request				body		SetNotificationChannelTogglesRequestBody	true	"Request params"
+//	@Param			notificationChannel	path		string										true	"name of the channel"	enums(push,email)
+//	@Success		200					{array}		notifications.NotificationChannelToggle
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404					{object}	server.ErrorResponse	"if user not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/notification-channels/{notificationChannel}/toggles [PUT].
+func (s *service) SetNotificationChannelToggles( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SetNotificationChannelTogglesRequestBody, []*notifications.NotificationChannelToggle],
+) (*server.Response[[]*notifications.NotificationChannelToggle], *server.Response[server.ErrorResponse]) {
+	if err := req.Data.validate(); err != nil {
+		return nil, server.UnprocessableEntity(errors.Wrap(err, "validations failed"), invalidPropertiesErrorCode)
+	}
+	resp, err := s.notificationsProcessor.SetNotificationChannelToggles(ctx, req.Data.NotificationChannel, req.Data.Toggles, req.AuthenticatedUser.UserID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to SetNotificationChannelToggles for %#v, userID:%v", req.Data, req.AuthenticatedUser.UserID)
+		switch {
+		case errors.Is(err, notifications.ErrRelationNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&resp), nil
+}
+
+func (arg *SetNotificationChannelTogglesRequestBody) validate() error {
+	all := notifications.AllNotificationDomains[arg.NotificationChannel]
+	if len(all) == 0 {
+		return errors.Errorf("invalid notificationChannel `%v`", arg.NotificationChannel)
+	}
+	if len(arg.Toggles) == 0 {
+		return errors.New("at least one toggle is required")
+	}
+	seenDomains, seenTypes := make(map[notifications.NotificationDomain]struct{}, len(arg.Toggles)), make(map[notifications.NotificationType]struct{})
+	for _, toggle := range arg.Toggles {
+		if toggle == nil {
+			return errors.New("toggles can't be null")
+		}
+		valid := false
+		for _, domain := range all {
+			if domain == toggle.Type {
+				valid = true
+
+				break
+			}
+		}
+		if !valid {
+			return errors.Errorf("invalid type `%v`", toggle.Type)
+		}
+		if _, seen := seenDomains[toggle.Type]; seen {
+			return errors.Errorf("duplicate type `%v`", toggle.Type)
+		}
+		seenDomains[toggle.Type] = struct{}{}
+		for _, typeToggle := range toggle.NotificationTypes {
+			if typeToggle == nil || notifications.NotificationTypeDomains[typeToggle.Type] != toggle.Type {
+				return errors.Errorf("invalid notificationTypes for type `%v`", toggle.Type)
+			}
+			if _, seen := seenTypes[typeToggle.Type]; seen {
+				return errors.Errorf("duplicate notification type `%v`", typeToggle.Type)
+			}
+			seenTypes[typeToggle.Type] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// ToggleNotificationChannelNotificationType godoc
+//
+//	@Schemes
+//	@Description	Toggles a specific notification type on/off, for a notification channel, overriding the toggle of its domain. `disable_all` still takes precedence.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string													true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			request				body	ToggleNotificationChannelNotificationTypeRequestBody	true	"Request params"
+//	@Param			notificationChannel	path	string													true	"name of the channel"	enums(push,email)
+//	@Param			notificationType	path	string													true	"the notification type"	enums(daily_bonus,new_contact,new_referral,news_added,ping,level_badge_unlocked,coin_badge_unlocked,social_badge_unlocked,role_changed,level_changed,mining_session_expiring,mining_session_expired,re_engagement_step_1,re_engagement_step_2,re_engagement_step_3)
+//	@Success		200					"ok"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404					{object}	server.ErrorResponse	"if user not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/notification-channels/{notificationChannel}/notification-types/{notificationType} [PUT].
+func (s *service) ToggleNotificationChannelNotificationType( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[ToggleNotificationChannelNotificationTypeRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := validateNotificationChannelNotificationType(req.Data.NotificationChannel, req.Data.NotificationType); err != nil {
+		return nil, server.UnprocessableEntity(errors.Wrap(err, "validations failed"), invalidPropertiesErrorCode)
+	}
+	if err := s.notificationsProcessor.ToggleNotificationChannelNotificationType(ctx, req.Data.NotificationChannel, req.Data.NotificationType, req.Data.Enabled, req.AuthenticatedUser.UserID); err != nil { //nolint:lll // .
+		err = errors.Wrapf(err, "failed to ToggleNotificationChannelNotificationType for %#v, userID:%v", req.Data, req.AuthenticatedUser.UserID)
+		switch {
+		case errors.Is(err, notifications.ErrRelationNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// ResetNotificationChannelNotificationType godoc
+//
+//	@Schemes
+//	@Description	Removes the toggle of a specific notification type, for a notification channel, so that it follows the toggle of its domain again.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationChannel	path	string	true	"name of the channel"		enums(push,email)
+//	@Param			notificationType	path	string	true	"the notification type"		enums(daily_bonus,new_contact,new_referral,news_added,ping,level_badge_unlocked,coin_badge_unlocked,social_badge_unlocked,role_changed,level_changed,mining_session_expiring,mining_session_expired,re_engagement_step_1,re_engagement_step_2,re_engagement_step_3)
+//	@Success		200					"ok"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/notification-channels/{notificationChannel}/notification-types/{notificationType} [DELETE].
+func (s *service) ResetNotificationChannelNotificationType( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[ResetNotificationChannelNotificationTypeArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := validateNotificationChannelNotificationType(req.Data.NotificationChannel, req.Data.NotificationType); err != nil {
+		return nil, server.UnprocessableEntity(errors.Wrap(err, "validations failed"), invalidPropertiesErrorCode)
+	}
+	if err := s.notificationsProcessor.ToggleNotificationChannelNotificationType(ctx, req.Data.NotificationChannel, req.Data.NotificationType, nil, req.AuthenticatedUser.UserID); err != nil { //nolint:lll // .
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to reset notification type toggle for %#v, userID:%v", req.Data, req.AuthenticatedUser.UserID))
+	}
+
+	return server.OK[any](), nil
+}
+
+func validateNotificationChannelNotificationType(channel notifications.NotificationChannel, notificationType notifications.NotificationType) error {
+	if len(notifications.AllNotificationDomains[channel]) == 0 {
+		return errors.Errorf("invalid notificationChannel `%v`", channel)
+	}
+	if _, found := notifications.NotificationTypeDomains[notificationType]; !found {
+		return errors.Errorf("invalid notificationType `%v`", notificationType)
+	}
+
+	return nil
+}
+
+// ConfirmEmailUnsubscription godoc
+//
+//	@Schemes
+//	@Description	Renders the page the unsubscribe links in the emails open. It changes nothing, it only asks the user to confirm, via `POST /email-unsubscriptions`.
+//	@Tags			Notifications
+//	@Produce		html
+//	@Param			token	query		string	true	"The token from the unsubscribe link"
+//	@Param			all		query		bool	false	"Unsubscribe from all emails, not just the ones of the domain the link was generated for"
+//	@Success		200		{string}	string	"the confirmation page"
+//	@Router			/email-unsubscriptions [GET].
+func (s *service) ConfirmEmailUnsubscription(ginCtx *gin.Context) {
+	query := url.Values{"token": []string{ginCtx.Query("token")}}
+	what := "these emails"
+	if all, _ := strconv.ParseBool(ginCtx.Query("all")); all { //nolint:errcheck // Anything else means `false`.
+		query.Set("all", "true")
+		what = "all emails"
+	}
+	page := fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"></head>
+<body><form method="POST" action="?%v"><p>Do you want to unsubscribe from %v?</p><button type="submit">Unsubscribe</button></form></body></html>`,
+		html.EscapeString(query.Encode()), what)
+	ginCtx.Header("Cache-Control", "no-store")
+	ginCtx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// UnsubscribeFromEmails godoc
+//
+//	@Schemes
+//	@Description	Unsubscribes the user the token was generated for from emails. It requires no authorization, because it's used by the unsubscribe links in the emails. The emails have no `List-Unsubscribe` headers yet, so it's not an RFC 8058 one-click unsubscribe endpoint.
+//	@Tags			Notifications
+//	@Produce		json
+//	@Param			token	query	string	true	"The token from the unsubscribe link"
+//	@Param			all		query	bool	false	"Unsubscribe from all emails, not just the ones of the domain the link was generated for"
+//	@Success		200		"ok"
+//	@Failure		400		{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		403		{object}	server.ErrorResponse	"if the token is invalid or expired"
+//	@Failure		404		{object}	server.ErrorResponse	"if user not found"
+//	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500		{object}	server.ErrorResponse
+//	@Failure		504		{object}	server.ErrorResponse	"if request times out"
+//	@Router			/email-unsubscriptions [POST].
+func (s *service) UnsubscribeFromEmails( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[UnsubscribeFromEmailsArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.notificationsProcessor.UnsubscribeFromEmails(ctx, req.Data.Token, req.Data.All); err != nil {
+		err = errors.Wrapf(err, "failed to UnsubscribeFromEmails for all:%v", req.Data.All) // The token is not logged, cuz it's a credential.
+		switch {
+		case errors.Is(err, notifications.ErrInvalidEmailUnsubscribeToken):
+			return nil, server.ForbiddenWithCode(err, invalidTokenErrorCode)
+		case errors.Is(err, notifications.ErrRelationNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// ProcessEmailEvents godoc
+//
+//	@Schemes
+//	@Description	Webhook for the bounce and complaint events of the email provider. The email addresses that permanently bounced or complained are not emailed anymore, until the user changes their email address.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Webhook-Secret	header	string							true	"The configured webhook secret"
+//	@Param			request				body	ProcessEmailEventsRequestBody	true	"Request params"
+//	@Success		200					"ok"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		403					{object}	server.ErrorResponse	"if the secret is invalid"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/email-events [POST].
+func (s *service) ProcessEmailEvents( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[ProcessEmailEventsRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.notificationsProcessor.ProcessEmailEvents(ctx, req.Data.Secret, req.Data.Events); err != nil {
+		err = errors.Wrapf(err, "failed to ProcessEmailEvents for %#v", req.Data.Events)
+		if errors.Is(err, notifications.ErrInvalidEmailEventsSecret) {
+			return nil, server.ForbiddenWithCode(err, invalidTokenErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
+
+// SendTestNotification godoc
+//
+//	@Schemes
+//	@Description	Renders the push, in-app and email notifications of the notification type for the user, exactly as they'd be sent for the message their source consumes, which is the `data`. Unless it's a `dryRun`, they're sent to the user too, even if they were already sent, without preventing the real ones from being sent later on.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string							true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationType	path		string							true	"the notification type"		enums(daily_bonus,new_contact,new_referral,ping,level_badge_unlocked,coin_badge_unlocked,social_badge_unlocked,role_changed,level_changed)
+//	@Param			request				body		SendTestNotificationRequestBody	true	"Request params"
+//	@Success		200					{object}	notifications.TestNotification
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if user not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or the notification type is not supported"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/test-notifications/{notificationType} [POST].
+func (s *service) SendTestNotification( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SendTestNotificationRequestBody, notifications.TestNotification],
+) (*server.Response[notifications.TestNotification], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAdmin(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	resp, err := s.notificationsProcessor.SendTestNotification(ctx, req.Data.NotificationType, req.Data.UserID, req.Data.Data, req.Data.DryRun)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to SendTestNotification for %#v", req.Data)
+		switch {
+		case errors.Is(err, notifications.ErrTestNotificationNotSupported):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode)
+		case errors.Is(err, notifications.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(resp), nil
+}
+
+// GetCapturedNotifications godoc
+//
+//	@Schemes
+//	@Description	Returns the notifications that were captured, instead of being sent, by the processors running in capture mode, newest first.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationChannel	query		string	false	"the notification channel"	enums(push,email,inapp)
+//	@Param			target				query		string	false	"the device token or the subscription topic, the email address, the user ID or the in-app feed"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Elements to skip before starting to look for"
+//	@Success		200					{array}		notifications.CapturedNotification
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/captured-notifications [GET].
+func (s *service) GetCapturedNotifications( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetCapturedNotificationsArg, []*notifications.CapturedNotification],
+) (*server.Response[[]*notifications.CapturedNotification], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAdmin(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	switch req.Data.NotificationChannel {
+	case "", notifications.PushNotificationChannel, notifications.EmailNotificationChannel, notifications.InAppNotificationChannel:
+	default:
+		return nil, server.BadRequest(errors.Errorf("invalid notificationChannel `%v`", req.Data.NotificationChannel), invalidPropertiesErrorCode)
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = 10
+	}
+	if req.Data.Limit > 1000 { //nolint:gomnd //.
+		req.Data.Limit = 1000
+	}
+	resp, err := s.notificationsProcessor.GetCapturedNotifications(
+		ctx, req.Data.NotificationChannel, req.Data.Target, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get captured notifications for %#v", req.Data))
+	}
+
+	return server.OK(&resp), nil
+}
+
 // GenerateInAppNotificationsUserAuthToken godoc
 //
 //	@Schemes
@@ -140,3 +523,11 @@ func (s *service) GenerateInAppNotificationsUserAuthToken( //nolint:gocritic //
 
 	return server.OK(token), nil
 }
+
+func verifyIfAdmin(usr *server.AuthenticatedUser) error {
+	if !strings.EqualFold(usr.Role, "admin") {
+		return errors.Errorf("access denied, invalid role `%v`", usr.Role)
+	}
+
+	return nil
+}
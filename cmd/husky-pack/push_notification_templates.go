@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/husky/notifications"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupPushNotificationTemplatesRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		GET("push-notification-templates/:notificationType", server.RootHandler(s.GetPushNotificationTemplates)).
+		GET("push-notification-templates/:notificationType/:language", server.RootHandler(s.GetPushNotificationTemplateVersions)).
+		POST("push-notification-templates/:notificationType/:language", server.RootHandler(s.CreatePushNotificationTemplate)).
+		POST("push-notification-templates/:notificationType/:language/versions/:version/preview", server.RootHandler(s.PreviewPushNotificationTemplate)).
+		PUT("push-notification-templates/:notificationType/:language/published", server.RootHandler(s.PublishPushNotificationTemplate))
+}
+
+// GetPushNotificationTemplates godoc
+//
+//	@Schemes
+//	@Description	Returns, for every language, the version of the push notification template of the notification type that's used. Version `0` is the one the service is built with.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationType	path		string	true	"the notification type"
+//	@Success		200					{array}		notifications.PushNotificationTemplate
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/push-notification-templates/{notificationType} [GET].
+func (s *service) GetPushNotificationTemplates( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetPushNotificationTemplatesArg, []*notifications.PushNotificationTemplate],
+) (*server.Response[[]*notifications.PushNotificationTemplate], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAdmin(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	if err := validatePushNotificationTemplate(req.Data.NotificationType, nil); err != nil {
+		return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+	}
+	resp, err := s.notificationsProcessor.GetPushNotificationTemplates(ctx, req.Data.NotificationType)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetPushNotificationTemplates for %#v", req.Data))
+	}
+
+	return server.OK(&resp), nil
+}
+
+// GetPushNotificationTemplateVersions godoc
+//
+//	@Schemes
+//	@Description	Returns every version of the push notification template of the notification type, in the language, newest first.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationType	path		string	true	"the notification type"
+//	@Param			language			path		string	true	"the language of the template"
+//	@Success		200					{array}		notifications.PushNotificationTemplate
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if there's no template"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/push-notification-templates/{notificationType}/{language} [GET].
+func (s *service) GetPushNotificationTemplateVersions( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetPushNotificationTemplateVersionsArg, []*notifications.PushNotificationTemplate],
+) (*server.Response[[]*notifications.PushNotificationTemplate], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAdmin(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	if err := validatePushNotificationTemplate(req.Data.NotificationType, &req.Data.Language); err != nil {
+		return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+	}
+	resp, err := s.notificationsProcessor.GetPushNotificationTemplateVersions(ctx, req.Data.NotificationType, req.Data.Language)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to GetPushNotificationTemplateVersions for %#v", req.Data)
+		switch {
+		case errors.Is(err, notifications.ErrNotFound):
+			return nil, server.NotFound(err, templateNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&resp), nil
+}
+
+// CreatePushNotificationTemplate godoc
+//
+//	@Schemes
+//	@Description	Creates a new version of the push notification template of the notification type, in the language. It's not used until it's published.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string										true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationType	path		string										true	"the notification type"
+//	@Param			language			path		string										true	"the language of the template"
+//	@Param			request				body		CreatePushNotificationTemplateRequestBody	true	"Request params"
+//	@Success		201					{object}	notifications.PushNotificationTemplate
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		409					{object}	server.ErrorResponse	"if another version was created in the meantime"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or the template is invalid"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/push-notification-templates/{notificationType}/{language} [POST].
+func (s *service) CreatePushNotificationTemplate( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[CreatePushNotificationTemplateRequestBody, notifications.PushNotificationTemplate],
+) (*server.Response[notifications.PushNotificationTemplate], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAdmin(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	if err := validatePushNotificationTemplate(req.Data.NotificationType, &req.Data.Language); err != nil {
+		return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+	}
+	tmpl := &notifications.PushNotificationTemplate{
+		NotificationType: req.Data.NotificationType,
+		Language:         req.Data.Language,
+		Title:            req.Data.Title,
+		Body:             req.Data.Body,
+	}
+	resp, err := s.notificationsProcessor.CreatePushNotificationTemplate(ctx, tmpl, req.AuthenticatedUser.UserID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to CreatePushNotificationTemplate for %#v", req.Data)
+		switch {
+		case errors.Is(err, notifications.ErrInvalidPushNotificationTemplate):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode)
+		case errors.Is(err, notifications.ErrDuplicate):
+			return nil, server.Conflict(err, raceConditionErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.Created(resp), nil
+}
+
+// PreviewPushNotificationTemplate godoc
+//
+//	@Schemes
+//	@Description	Renders a version of the push notification template, for the provided data or, if not provided, for sample data.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string										true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationType	path		string										true	"the notification type"
+//	@Param			language			path		string										true	"the language of the template"
+//	@Param			version				path		uint64										true	"the version of the template. `0` is the one the service is built with"
+//	@Param			request				body		PreviewPushNotificationTemplateRequestBody	true	"Request params"
+//	@Success		200					{object}	notifications.PushNotificationTemplatePreview
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the version is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or it can't be rendered with the data"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/push-notification-templates/{notificationType}/{language}/versions/{version}/preview [POST].
+func (s *service) PreviewPushNotificationTemplate( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[PreviewPushNotificationTemplateRequestBody, notifications.PushNotificationTemplatePreview],
+) (*server.Response[notifications.PushNotificationTemplatePreview], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAdmin(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	if err := validatePushNotificationTemplate(req.Data.NotificationType, &req.Data.Language); err != nil {
+		return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+	}
+	resp, err := s.notificationsProcessor.PreviewPushNotificationTemplate(ctx, req.Data.NotificationType, req.Data.Language, req.Data.Version, req.Data.Data)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to PreviewPushNotificationTemplate for %#v", req.Data)
+		switch {
+		case errors.Is(err, notifications.ErrNotFound):
+			return nil, server.NotFound(err, templateNotFoundErrorCode)
+		case errors.Is(err, notifications.ErrInvalidPushNotificationTemplate):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(resp), nil
+}
+
+// PublishPushNotificationTemplate godoc
+//
+//	@Schemes
+//	@Description	Starts using a version of the push notification template of the notification type, in the language, instead of the current one. Publish an older version to roll back to it, or `0` to go back to the one the service is built with.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string										true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			notificationType	path	string										true	"the notification type"
+//	@Param			language			path	string										true	"the language of the template"
+//	@Param			request				body	PublishPushNotificationTemplateRequestBody	true	"Request params"
+//	@Success		200					"ok"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the version is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/push-notification-templates/{notificationType}/{language}/published [PUT].
+func (s *service) PublishPushNotificationTemplate( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[PublishPushNotificationTemplateRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := verifyIfAdmin(&req.AuthenticatedUser); err != nil {
+		return nil, server.Forbidden(err)
+	}
+	if err := validatePushNotificationTemplate(req.Data.NotificationType, &req.Data.Language); err != nil {
+		return nil, server.BadRequest(err, invalidPropertiesErrorCode)
+	}
+	if err := s.notificationsProcessor.PublishPushNotificationTemplate(ctx, req.Data.NotificationType, req.Data.Language, *req.Data.Version, req.AuthenticatedUser.UserID); err != nil { //nolint:lll // .
+		err = errors.Wrapf(err, "failed to PublishPushNotificationTemplate for %#v", req.Data)
+		switch {
+		case errors.Is(err, notifications.ErrNotFound), errors.Is(err, notifications.ErrRelationNotFound):
+			return nil, server.NotFound(err, templateNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// validatePushNotificationTemplate also normalizes the language, which can have a region too, I.E. `pt_BR` becomes `pt-br`.
+func validatePushNotificationTemplate(notificationType notifications.NotificationType, language *string) error {
+	valid := false
+	for _, nt := range notifications.AllNotificationTypes {
+		if nt == notificationType {
+			valid = true
+
+			break
+		}
+	}
+	if !valid {
+		return errors.Errorf("invalid notificationType `%v`", notificationType)
+	}
+	if language == nil {
+		return nil
+	}
+	if *language = strings.Trim(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(*language), "_", "-")), "-"); *language == "" {
+		return errors.New("invalid language")
+	}
+
+	return nil
+}
@@ -60,7 +60,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit of elements to return. Defaults to 10",
+                        "description": "Limit of elements to return. Defaults to 10. For ` + "`" + `featured` + "`" + `, at most the configured number of featured slots are returned.",
                         "name": "limit",
                         "in": "query"
                     },
@@ -75,6 +75,12 @@ const docTemplate = `{
                         "description": "Example ` + "`" + `2022-01-03T16:20:52.156534Z` + "`" + `. If unspecified, the creation date of the news articles will be ignored.",
                         "name": "createdAfter",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ETag of a previously returned response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -87,6 +93,9 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "304": {
+                        "description": "if the response didn't change"
+                    },
                     "400": {
                         "description": "if validations fail",
                         "schema": {
@@ -120,6 +129,80 @@ const docTemplate = `{
                 }
             }
         },
+        "/news/{language}/feed.{format}": {
+            "get": {
+                "description": "Returns the latest public news of a language as a RSS 2.0, Atom or JSON Feed 1.1 document. Supports conditional GET.",
+                "produces": [
+                    "text/xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The language of the news",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "rss",
+                            "atom",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "The feed format",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ETag of a previously returned feed",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The Last-Modified of a previously returned feed",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "the feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "if the feed didn't change"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/notification-channels/{notificationChannel}/toggles": {
             "get": {
                 "description": "Returns the user's list of notification channel toggles for the provided notificationChannel.",
@@ -229,6 +312,12 @@ const docTemplate = `{
                         "description": "Example ` + "`" + `2022-01-03T16:20:52.156534Z` + "`" + `. If unspecified, the creation date of the news articles will be ignored.",
                         "name": "createdAfter",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ETag of a previously returned response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -238,6 +327,9 @@ const docTemplate = `{
                             "$ref": "#/definitions/news.UnreadNewsCount"
                         }
                     },
+                    "304": {
+                        "description": "if the response didn't change"
+                    },
                     "401": {
                         "description": "if not authorized",
                         "schema": {
@@ -274,6 +366,11 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2022-01-03T16:20:52.156534Z"
                 },
+                "draft": {
+                    "description": "Drafts are not visible to users and nobody is notified about them until they're published.",
+                    "type": "boolean",
+                    "example": false
+                },
                 "id": {
                     "type": "string",
                     "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
@@ -302,6 +399,39 @@ const docTemplate = `{
                         ]
                     }
                 },
+                "pinnedUntil": {
+                    "description": "Only for ` + "`" + `featured` + "`" + ` news. Until then, it's shown before the other featured news, regardless of priority.",
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "priority": {
+                    "description": "Only for ` + "`" + `featured` + "`" + ` news. The ones with higher priorities are shown first.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "reaction": {
+                    "description": "The reaction of the authorized user, if any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/news.Reaction"
+                        }
+                    ],
+                    "example": "like"
+                },
+                "reactions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "targeting": {
+                    "description": "If set, only the users matching it are going to see it/get notified about it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.Targeting"
+                        }
+                    ]
+                },
                 "title": {
                     "type": "string",
                     "example": "The importance of the blockchain technology"
@@ -322,6 +452,18 @@ const docTemplate = `{
                     "type": "string",
                     "example": "https://somewebsite.com/blockchain"
                 },
+                "variant": {
+                    "description": "The variant the authorized user was bucketed into, if the news article has Variants.",
+                    "type": "string",
+                    "example": "b"
+                },
+                "variants": {
+                    "description": "If set, users are split evenly and deterministically between the original title/image and each of these.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/news.Variant"
+                    }
+                },
                 "viewed": {
                     "type": "boolean",
                     "example": true
@@ -332,6 +474,15 @@ const docTemplate = `{
                 }
             }
         },
+        "news.Reaction": {
+            "type": "string",
+            "enum": [
+                "like"
+            ],
+            "x-enum-varnames": [
+                "LikeReaction"
+            ]
+        },
         "news.Type": {
             "type": "string",
             "enum": [
@@ -352,6 +503,26 @@ const docTemplate = `{
                 }
             }
         },
+        "news.Variant": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Has to be unique per news article and different from ` + "`" + `control` + "`" + `.",
+                    "type": "string",
+                    "example": "b"
+                },
+                "imageUrl": {
+                    "description": "Optional. If empty, the original image is used.",
+                    "type": "string",
+                    "example": "https://somewebsite.com/blockchain-b.jpg"
+                },
+                "title": {
+                    "description": "Optional. If empty, the original title is used.",
+                    "type": "string",
+                    "example": "Why blockchain matters"
+                }
+            }
+        },
         "notifications.NotificationChannelToggle": {
             "type": "object",
             "properties": {
@@ -359,6 +530,13 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": true
                 },
+                "notificationTypes": {
+                    "description": "The notification types of the domain, which can be toggled individually, overriding the domain toggle.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.NotificationTypeToggle"
+                    }
+                },
                 "type": {
                     "allOf": [
                         {
@@ -398,6 +576,95 @@ const docTemplate = `{
                 "SystemNotificationDomain"
             ]
         },
+        "notifications.NotificationType": {
+            "type": "string",
+            "enum": [
+                "adoption_changed",
+                "daily_bonus",
+                "new_contact",
+                "new_referral",
+                "news_added",
+                "ping",
+                "level_badge_unlocked",
+                "coin_badge_unlocked",
+                "social_badge_unlocked",
+                "role_changed",
+                "level_changed"
+            ],
+            "x-enum-varnames": [
+                "AdoptionChangedNotificationType",
+                "DailyBonusNotificationType",
+                "NewContactNotificationType",
+                "NewReferralNotificationType",
+                "NewsAddedNotificationType",
+                "PingNotificationType",
+                "LevelBadgeUnlockedNotificationType",
+                "CoinBadgeUnlockedNotificationType",
+                "SocialBadgeUnlockedNotificationType",
+                "RoleChangedNotificationType",
+                "LevelChangedNotificationType"
+            ]
+        },
+        "notifications.NotificationTypeToggle": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Either the override, if any, or the domain toggle.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "overridden": {
+                    "description": "Whether it's toggled individually, rather than inheriting the domain toggle.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/notifications.NotificationType"
+                        }
+                    ],
+                    "example": "ping"
+                }
+            }
+        },
+        "notifications.Targeting": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "description": "ISO 3166-1 alpha-2 country codes. The user's country has to be one of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "US",
+                        "RO"
+                    ]
+                },
+                "maxAppVersion": {
+                    "description": "Inclusive.",
+                    "type": "string",
+                    "example": "2.0.0"
+                },
+                "minAppVersion": {
+                    "description": "Inclusive.",
+                    "type": "string",
+                    "example": "1.2.0"
+                },
+                "segments": {
+                    "description": "The user has to be part of at least one of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "has_referrals",
+                            "new_user"
+                        ]
+                    }
+                }
+            }
+        },
         "server.ErrorResponse": {
             "type": "object",
             "properties": {
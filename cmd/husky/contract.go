@@ -20,10 +20,12 @@ type (
 		Language     string    `uri:"language" example:"en" required:"true"`
 		Limit        uint64    `form:"limit" maximum:"1000" example:"10"` // 10 by default.
 		Offset       uint64    `form:"offset" example:"5"`
+		IfNoneMatch  string    `header:"If-None-Match" swaggerignore:"true"`
 	}
 	GetUnreadNewsCountArg struct {
 		CreatedAfter string `form:"createdAfter" example:"2022-01-03T16:20:52.156534Z"`
 		Language     string `uri:"language" example:"en" required:"true"`
+		IfNoneMatch  string `header:"If-None-Match" swaggerignore:"true"`
 	}
 )
 
@@ -241,6 +243,11 @@ type (
 		cfg                     *config
 	}
 	config struct {
+		NewsFeed struct {
+			Title       string `yaml:"title"`
+			Description string `yaml:"description"`
+			Link        string `yaml:"link"`
+		} `yaml:"newsFeed"`
 		Host    string `yaml:"host"`
 		Version string `yaml:"version"`
 	}
@@ -4,9 +4,13 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
 	"strings"
 	stdlibtime "time"
 
+	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/husky/news"
@@ -18,7 +22,10 @@ func (s *service) setupNewsRoutes(router *server.Router) {
 	router.
 		Group("v1r").
 		GET("news/:language", server.RootHandler(s.GetNews)).
-		GET("unread-news-count/:language", server.RootHandler(s.GetUnreadNewsCount))
+		GET("unread-news-count/:language", server.RootHandler(s.GetUnreadNewsCount)).
+		GET("news/:language/feed.rss", s.GetNewsFeed(rssNewsFeedFormat)).
+		GET("news/:language/feed.atom", s.GetNewsFeed(atomNewsFeedFormat)).
+		GET("news/:language/feed.json", s.GetNewsFeed(jsonNewsFeedFormat))
 }
 
 // GetNews godoc
@@ -28,13 +35,15 @@ func (s *service) setupNewsRoutes(router *server.Router) {
 //	@Tags			News
 //	@Accept			json
 //	@Produce		json
-//	@Param			Authorization	header		string	true	"Insert your access token"							default(Bearer <Add access token here>)
-//	@Param			type			query		string	false	"type of news to look for. Default is `regular`."	enums(regular,featured)
-//	@Param			language		path		string	true	"the language of the news article"
-//	@Param			limit			query		uint64	false	"Limit of elements to return. Defaults to 10"
-//	@Param			offset			query		uint64	false	"Elements to skip before starting to look for"
-//	@Param			createdAfter	query		string	false	"Example `2022-01-03T16:20:52.156534Z`. If unspecified, the creation date of the news articles will be ignored."
-//	@Success		200				{array}		news.PersonalNews
+//	@Param			Authorization	header	string	true	"Insert your access token"							default(Bearer <Add access token here>)
+//	@Param			type			query	string	false	"type of news to look for. Default is `regular`."	enums(regular,featured)
+//	@Param			language		path	string	true	"the language of the news article"
+//	@Param			limit			query	uint64	false	"Limit of elements to return. Defaults to 10. For `featured`, at most the configured number of featured slots are returned."
+//	@Param			offset			query	uint64	false	"Elements to skip before starting to look for"
+//	@Param			createdAfter	query	string	false	"Example `2022-01-03T16:20:52.156534Z`. If unspecified, the creation date of the news articles will be ignored."
+//	@Param			If-None-Match	header	string	false	"The ETag of a previously returned response"
+//	@Success		200				{array}	news.PersonalNews
+//	@Success		304				"if the response didn't change"
 //	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
@@ -57,9 +66,6 @@ func (s *service) GetNews( //nolint:gocritic,funlen // False negative.
 	if req.Data.Type == "" {
 		req.Data.Type = news.RegularNewsType
 	}
-	if req.Data.Type == news.FeaturedNewsType {
-		req.Data.Limit, req.Data.Offset = 1, 0
-	}
 	if req.Data.Limit == 0 {
 		req.Data.Limit = 10
 	}
@@ -73,12 +79,16 @@ func (s *service) GetNews( //nolint:gocritic,funlen // False negative.
 	if req.Data.Type != news.RegularNewsType && req.Data.Type != news.FeaturedNewsType {
 		return nil, server.BadRequest(errors.Errorf("invalid type %v", req.Data.Type), invalidPropertiesErrorCode)
 	}
+	ctx, err := s.contextWithAudience(ctx, req.AuthenticatedUser.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get audience for userID:%v", req.AuthenticatedUser.UserID))
+	}
 	resp, err := s.newsRepository.GetNews(ctx, req.Data.Type, req.Data.Language, req.Data.Limit, req.Data.Offset, createdAfter)
 	if err != nil {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to get news by %#v", req.Data))
 	}
 
-	return server.OK(&resp), nil
+	return okOrNotModified(&resp, req.Data.IfNoneMatch)
 }
 
 // GetUnreadNewsCount godoc
@@ -91,7 +101,9 @@ func (s *service) GetNews( //nolint:gocritic,funlen // False negative.
 //	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
 //	@Param			language		path		string	true	"The language of the news to be counted"
 //	@Param			createdAfter	query		string	false	"Example `2022-01-03T16:20:52.156534Z`. If unspecified, the creation date of the news articles will be ignored."
+//	@Param			If-None-Match	header		string	false	"The ETag of a previously returned response"
 //	@Success		200				{object}	news.UnreadNewsCount
+//	@Success		304				"if the response didn't change"
 //	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500				{object}	server.ErrorResponse
@@ -114,10 +126,44 @@ func (s *service) GetUnreadNewsCount( //nolint:gocritic // False negative.
 	if _, validLanguage := languages[req.Data.Language]; !validLanguage {
 		return nil, server.BadRequest(errors.Errorf("invalid language `%v`", req.Data.Language), invalidPropertiesErrorCode)
 	}
+	ctx, err := s.contextWithAudience(ctx, req.AuthenticatedUser.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get audience for userID:%v", req.AuthenticatedUser.UserID))
+	}
 	resp, err := s.newsRepository.GetUnreadNewsCount(ctx, req.Data.Language, createdAfter)
 	if err != nil {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to get unread news count for userID:%v", req.AuthenticatedUser.UserID))
 	}
 
-	return server.OK(resp), nil
+	return okOrNotModified(resp, req.Data.IfNoneMatch)
+}
+
+// okOrNotModified responds with 304 and no body, if the client already has the exact same response.
+func okOrNotModified[RESP any](resp *RESP, ifNoneMatch string) (*server.Response[RESP], *server.Response[server.ErrorResponse]) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to marshal %#v", resp))
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write(body) //nolint:errcheck // It never fails.
+	headers := map[string]string{
+		"ETag":          fmt.Sprintf(`"%x"`, hash.Sum64()),
+		"Cache-Control": "private, no-cache",
+	}
+	if ifNoneMatch != "" && etagMatches(ifNoneMatch, headers["ETag"]) {
+		return &server.Response[RESP]{Code: http.StatusNotModified, Headers: headers}, nil
+	}
+	success := server.OK(resp)
+	success.Headers = headers
+
+	return success, nil
+}
+
+func (s *service) contextWithAudience(ctx context.Context, userID string) (context.Context, error) {
+	audience, err := s.notificationsRepository.GetAudience(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to GetAudience for userID:%v", userID)
+	}
+
+	return news.ContextWithAudience(ctx, audience), nil
 }
@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/husky/news"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+type (
+	newsFeedFormat string
+	rssFeed        struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Atom    string     `xml:"xmlns:atom,attr"`
+		Media   string     `xml:"xmlns:media,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+	rssChannel struct {
+		Self          atomLink   `xml:"atom:link"`
+		Title         string     `xml:"title"`
+		Link          string     `xml:"link"`
+		Description   string     `xml:"description"`
+		Language      string     `xml:"language"`
+		LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+		Items         []*rssItem `xml:"item"`
+	}
+	rssItem struct {
+		Image      *rssMediaContent `xml:"media:content,omitempty"`
+		GUID       rssGUID          `xml:"guid"`
+		Title      string           `xml:"title"`
+		Link       string           `xml:"link"`
+		PubDate    string           `xml:"pubDate"`
+		Categories []string         `xml:"category"`
+	}
+	rssGUID struct {
+		Value       string `xml:",chardata"`
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+	}
+	// The image is a Media RSS content, instead of an enclosure, cuz enclosures require the length, which we don't know.
+	rssMediaContent struct {
+		URL    string `xml:"url,attr"`
+		Type   string `xml:"type,attr,omitempty"`
+		Medium string `xml:"medium,attr"`
+	}
+	atomFeed struct {
+		XMLName  xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+		Language string       `xml:"xml:lang,attr"`
+		ID       string       `xml:"id"`
+		Title    string       `xml:"title"`
+		Subtitle string       `xml:"subtitle,omitempty"`
+		Updated  string       `xml:"updated"`
+		Links    []atomLink   `xml:"link"`
+		Entries  []*atomEntry `xml:"entry"`
+	}
+	atomEntry struct {
+		ID         string         `xml:"id"`
+		Title      string         `xml:"title"`
+		Published  string         `xml:"published"`
+		Updated    string         `xml:"updated"`
+		Links      []atomLink     `xml:"link"`
+		Categories []atomCategory `xml:"category"`
+	}
+	atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+		Type string `xml:"type,attr,omitempty"`
+	}
+	atomCategory struct {
+		Term string `xml:"term,attr"`
+	}
+	jsonFeed struct {
+		Version     string          `json:"version"`
+		Title       string          `json:"title"`
+		HomePageURL string          `json:"home_page_url"` //nolint:tagliatelle // It's the JSON Feed spec.
+		FeedURL     string          `json:"feed_url"`      //nolint:tagliatelle // It's the JSON Feed spec.
+		Description string          `json:"description,omitempty"`
+		Language    string          `json:"language"`
+		Items       []*jsonFeedItem `json:"items"`
+	}
+	jsonFeedItem struct {
+		ID            string   `json:"id"`
+		URL           string   `json:"url"`
+		Title         string   `json:"title"`
+		ContentText   string   `json:"content_text"` //nolint:tagliatelle // It's the JSON Feed spec.
+		Image         string   `json:"image,omitempty"`
+		DatePublished string   `json:"date_published"` //nolint:tagliatelle // It's the JSON Feed spec.
+		DateModified  string   `json:"date_modified"`  //nolint:tagliatelle // It's the JSON Feed spec.
+		Tags          []string `json:"tags,omitempty"`
+	}
+)
+
+const (
+	rssNewsFeedFormat  newsFeedFormat = "rss"
+	atomNewsFeedFormat newsFeedFormat = "atom"
+	jsonNewsFeedFormat newsFeedFormat = "json"
+
+	newsFeedLimit   = 50
+	newsFeedTimeout = 30 * stdlibtime.Second
+)
+
+// GetNewsFeed godoc
+//
+//	@Schemes
+//	@Description	Returns the latest public news of a language as a RSS 2.0, Atom or JSON Feed 1.1 document. Supports conditional GET.
+//	@Tags			News
+//	@Produce		xml
+//	@Produce		json
+//	@Param			language			path		string	true	"The language of the news"
+//	@Param			format				path		string	true	"The feed format"	Enums(rss, atom, json)
+//	@Param			If-None-Match		header		string	false	"The ETag of a previously returned feed"
+//	@Param			If-Modified-Since	header		string	false	"The Last-Modified of a previously returned feed"
+//	@Success		200					{string}	string	"the feed"
+//	@Success		304					"if the feed didn't change"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/news/{language}/feed.{format} [GET].
+func (s *service) GetNewsFeed(format newsFeedFormat) gin.HandlerFunc { //nolint:funlen // .
+	return func(ginCtx *gin.Context) {
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), newsFeedTimeout)
+		defer cancel()
+		language := strings.ToLower(ginCtx.Param("language"))
+		if _, validLanguage := languages[language]; !validLanguage {
+			ginCtx.JSON(http.StatusBadRequest, server.BadRequest(errors.Errorf("invalid language `%v`", language), invalidPropertiesErrorCode).Data)
+
+			return
+		}
+		nws, err := s.newsRepository.GetNewsFeed(ctx, language, newsFeedLimit)
+		if err != nil {
+			log.Error(errors.Wrapf(err, "failed to GetNewsFeed for language:%v", language))
+			ginCtx.JSON(http.StatusInternalServerError, server.Unexpected(errors.New("oops, something went wrong")).Data)
+
+			return
+		}
+		lastModified := lastNewsFeedUpdate(nws)
+		etag := fmt.Sprintf(`"%v-%v-%v"`, format, lastModified.UnixNano(), len(nws))
+		ginCtx.Header("ETag", etag)
+		ginCtx.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+		ginCtx.Header("Cache-Control", "public, max-age=60")
+		if notModified(ginCtx.Request, etag, lastModified) {
+			ginCtx.Status(http.StatusNotModified)
+
+			return
+		}
+		selfURL := fmt.Sprintf("https://%v%v", s.cfg.Host, ginCtx.Request.URL.Path)
+		var body []byte
+		var contentType string
+		switch format {
+		case rssNewsFeedFormat:
+			contentType = "application/rss+xml; charset=utf-8"
+			body, err = xml.Marshal(s.rssNewsFeed(language, selfURL, lastModified, nws))
+		case atomNewsFeedFormat:
+			contentType = "application/atom+xml; charset=utf-8"
+			body, err = xml.Marshal(s.atomNewsFeed(language, selfURL, lastModified, nws))
+		case jsonNewsFeedFormat:
+			contentType = "application/feed+json; charset=utf-8"
+			body, err = json.MarshalContext(ctx, s.jsonNewsFeed(language, selfURL, nws))
+		}
+		if err != nil {
+			log.Error(errors.Wrapf(err, "failed to marshal %v news feed for language:%v", format, language))
+			ginCtx.JSON(http.StatusInternalServerError, server.Unexpected(errors.New("oops, something went wrong")).Data)
+
+			return
+		}
+		if format != jsonNewsFeedFormat {
+			body = append([]byte(xml.Header), body...)
+		}
+		ginCtx.Data(http.StatusOK, contentType, body)
+	}
+}
+
+func lastNewsFeedUpdate(nws []*news.TaggedNews) stdlibtime.Time {
+	lastModified := stdlibtime.Unix(0, 0).UTC()
+	for _, elem := range nws {
+		if elem.UpdatedAt != nil && elem.UpdatedAt.After(lastModified) {
+			lastModified = *elem.UpdatedAt.Time
+		}
+	}
+
+	return lastModified.UTC().Truncate(stdlibtime.Second)
+}
+
+func notModified(req *http.Request, etag string, lastModified stdlibtime.Time) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince := req.Header.Get("If-Modified-Since"); ifModifiedSince != "" {
+		if since, err := http.ParseTime(ifModifiedSince); err == nil {
+			return !lastModified.After(since)
+		}
+	}
+
+	return false
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/"); candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *service) rssNewsFeed(language, selfURL string, lastModified stdlibtime.Time, nws []*news.TaggedNews) *rssFeed {
+	feed := &rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Media:   "http://search.yahoo.com/mrss/",
+		Channel: rssChannel{
+			Self:          atomLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+			Title:         s.cfg.NewsFeed.Title,
+			Link:          s.cfg.NewsFeed.Link,
+			Description:   s.cfg.NewsFeed.Description,
+			Language:      language,
+			LastBuildDate: lastModified.Format(stdlibtime.RFC1123Z),
+			Items:         make([]*rssItem, 0, len(nws)),
+		},
+	}
+	for _, elem := range nws {
+		item := &rssItem{
+			GUID:       rssGUID{Value: fmt.Sprintf("%v:%v", elem.Language, elem.ID)},
+			Title:      elem.Title,
+			Link:       elem.URL,
+			PubDate:    elem.CreatedAt.Format(stdlibtime.RFC1123Z),
+			Categories: newsFeedTags(elem),
+		}
+		if elem.ImageURL != "" {
+			item.Image = &rssMediaContent{URL: elem.ImageURL, Type: imageMimeType(elem.ImageURL), Medium: "image"}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return feed
+}
+
+func (s *service) atomNewsFeed(language, selfURL string, lastModified stdlibtime.Time, nws []*news.TaggedNews) *atomFeed {
+	feed := &atomFeed{
+		Language: language,
+		ID:       selfURL,
+		Title:    s.cfg.NewsFeed.Title,
+		Subtitle: s.cfg.NewsFeed.Description,
+		Updated:  lastModified.Format(stdlibtime.RFC3339),
+		Links:    []atomLink{{Href: selfURL, Rel: "self", Type: "application/atom+xml"}, {Href: s.cfg.NewsFeed.Link, Rel: "alternate"}},
+		Entries:  make([]*atomEntry, 0, len(nws)),
+	}
+	for _, elem := range nws {
+		entry := &atomEntry{
+			ID:        fmt.Sprintf("urn:ice:news:%v:%v", elem.Language, elem.ID),
+			Title:     elem.Title,
+			Published: elem.CreatedAt.Format(stdlibtime.RFC3339),
+			Updated:   elem.UpdatedAt.Format(stdlibtime.RFC3339),
+			Links:     []atomLink{{Href: elem.URL, Rel: "alternate"}},
+		}
+		if elem.ImageURL != "" {
+			entry.Links = append(entry.Links, atomLink{Href: elem.ImageURL, Rel: "enclosure", Type: imageMimeType(elem.ImageURL)})
+		}
+		for _, tag := range newsFeedTags(elem) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed
+}
+
+func (s *service) jsonNewsFeed(language, selfURL string, nws []*news.TaggedNews) *jsonFeed {
+	feed := &jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       s.cfg.NewsFeed.Title,
+		HomePageURL: s.cfg.NewsFeed.Link,
+		FeedURL:     selfURL,
+		Description: s.cfg.NewsFeed.Description,
+		Language:    language,
+		Items:       make([]*jsonFeedItem, 0, len(nws)),
+	}
+	for _, elem := range nws {
+		feed.Items = append(feed.Items, &jsonFeedItem{
+			ID:            fmt.Sprintf("%v:%v", elem.Language, elem.ID),
+			URL:           elem.URL,
+			Title:         elem.Title,
+			ContentText:   elem.Title,
+			Image:         elem.ImageURL,
+			DatePublished: elem.CreatedAt.Format(stdlibtime.RFC3339),
+			DateModified:  elem.UpdatedAt.Format(stdlibtime.RFC3339),
+			Tags:          newsFeedTags(elem),
+		})
+	}
+
+	return feed
+}
+
+func newsFeedTags(nws *news.TaggedNews) []string {
+	if nws.Tags == nil {
+		return nil
+	}
+
+	return *nws.Tags
+}
+
+// imageMimeType is based on the file extension of the image, because that's what the picture client keeps. It's empty if unknown.
+func imageMimeType(url string) string {
+	if queryIdx := strings.IndexAny(url, "?#"); queryIdx >= 0 {
+		url = url[:queryIdx]
+	}
+	switch strings.ToLower(path.Ext(url)) {
+	case ".png":
+		return "image/png"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".webp":
+		return "image/webp"
+	case ".gif":
+		return "image/gif"
+	case ".avif":
+		return "image/avif"
+	case ".svg":
+		return "image/svg+xml"
+	default:
+		return ""
+	}
+}
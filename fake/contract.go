@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: ice License 1.0
+
+// Package fake provides in-process fakes of the push, email and tracking providers, to run the whole pipeline offline, I.E. in integration tests.
+// They record every call and can be made to fail.
+package fake
+
+import (
+	"sync"
+
+	"github.com/ice-blockchain/wintr/analytics/tracking"
+	"github.com/ice-blockchain/wintr/notifications/push"
+)
+
+// Public API.
+
+// AnyTarget matches every target, when injecting failures.
+const AnyTarget = ""
+
+type (
+	// PushClient is a fake push.Client. The targets are the device tokens and the subscription topics.
+	PushClient struct {
+		failures
+		sent               []*push.Notification[push.DeviceToken]
+		broadcasted        []*push.Notification[push.SubscriptionTopic]
+		broadcastedDelayed []*push.DelayedNotification
+		mx                 sync.Mutex
+	}
+	// EmailClient is a fake email.Client. The targets are the email addresses.
+	EmailClient struct {
+		failures
+		sent []*Email
+		mx   sync.Mutex
+	}
+	// TrackingClient is a fake tracking.Client. The targets are the user IDs.
+	TrackingClient struct {
+		failures
+		trackedActions    []*TrackedAction
+		setUserAttributes []*UserAttributes
+		deletedUsers      []string
+		mx                sync.Mutex
+	}
+	// Email is an email sent to a single participant.
+	Email struct {
+		Subject string
+		Body    string
+		From    string
+		To      string
+	}
+	TrackedAction struct {
+		Action *tracking.Action
+		UserID string
+	}
+	UserAttributes struct {
+		Attributes map[string]any
+		UserID     string
+	}
+)
+
+// Private API.
+
+type (
+	// | failures are the errors the calls of a client fail with, by target.
+	failures struct {
+		byTarget map[string]error
+		mx       sync.RWMutex
+	}
+)
+
+//nolint:gochecknoglobals // They're shared by the processors and the tests that started them.
+var (
+	pushClients     = make(map[string]*PushClient)
+	emailClients    = make(map[string]*EmailClient)
+	trackingClients = make(map[string]*TrackingClient)
+	clientsMx       sync.Mutex
+)
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package fake
+
+import (
+	"context"
+
+	"github.com/ice-blockchain/wintr/email"
+)
+
+// Send records an email for each participant. It fails for all of them, if it fails for any of them, just like the real one.
+func (c *EmailClient) Send(ctx context.Context, parcel *email.Parcel, participants ...email.Participant) error {
+	emails := make([]*Email, 0, len(participants))
+	for _, participant := range participants {
+		if err := c.failureOf(ctx, participant.Email); err != nil {
+			return err
+		}
+		em := &Email{Subject: parcel.Subject, From: parcel.From.Email, To: participant.Email}
+		if parcel.Body != nil {
+			em.Body = parcel.Body.Data
+		}
+		emails = append(emails, em)
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.sent = append(c.sent, emails...)
+
+	return nil
+}
+
+// Sent returns the emails sent, in the order they were sent.
+func (c *EmailClient) Sent() []*Email {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return append(make([]*Email, 0, len(c.sent)), c.sent...)
+}
+
+// Reset forgets everything that was sent, but not the injected failures.
+func (c *EmailClient) Reset() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.sent = nil
+}
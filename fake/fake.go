@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package fake
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// NewPushClient returns the fake push client of the application yaml key. Repeated calls return the same one,
+// so that tests can check what the processors they started sent and make them fail.
+func NewPushClient(applicationYAMLKey string) *PushClient {
+	clientsMx.Lock()
+	defer clientsMx.Unlock()
+	if _, found := pushClients[applicationYAMLKey]; !found {
+		pushClients[applicationYAMLKey] = new(PushClient)
+	}
+
+	return pushClients[applicationYAMLKey]
+}
+
+// NewEmailClient returns the fake email client of the application yaml key. Repeated calls return the same one.
+func NewEmailClient(applicationYAMLKey string) *EmailClient {
+	clientsMx.Lock()
+	defer clientsMx.Unlock()
+	if _, found := emailClients[applicationYAMLKey]; !found {
+		emailClients[applicationYAMLKey] = new(EmailClient)
+	}
+
+	return emailClients[applicationYAMLKey]
+}
+
+// NewTrackingClient returns the fake tracking client of the application yaml key. Repeated calls return the same one.
+func NewTrackingClient(applicationYAMLKey string) *TrackingClient {
+	clientsMx.Lock()
+	defer clientsMx.Unlock()
+	if _, found := trackingClients[applicationYAMLKey]; !found {
+		trackingClients[applicationYAMLKey] = new(TrackingClient)
+	}
+
+	return trackingClients[applicationYAMLKey]
+}
+
+// InjectFailure makes every call for the target fail with the error, until it's removed with a nil error. Use AnyTarget for all of them.
+// I.E. `push.ErrInvalidDeviceToken`, for a device token.
+func (f *failures) InjectFailure(target string, err error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	if err == nil {
+		delete(f.byTarget, target)
+
+		return
+	}
+	if f.byTarget == nil {
+		f.byTarget = make(map[string]error, 1)
+	}
+	f.byTarget[target] = err
+}
+
+func (f *failures) failureOf(ctx context.Context, target string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	f.mx.RLock()
+	defer f.mx.RUnlock()
+	if err, found := f.byTarget[target]; found {
+		return err
+	}
+
+	return f.byTarget[AnyTarget]
+}
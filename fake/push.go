@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package fake
+
+import (
+	"context"
+
+	"github.com/ice-blockchain/wintr/notifications/push"
+)
+
+func (c *PushClient) Send(ctx context.Context, pn *push.Notification[push.DeviceToken], responder chan<- error) {
+	if err := c.failureOf(ctx, string(pn.Target)); err != nil {
+		responder <- err
+
+		return
+	}
+	c.mx.Lock()
+	c.sent = append(c.sent, pn)
+	c.mx.Unlock()
+	responder <- nil
+}
+
+func (c *PushClient) Broadcast(ctx context.Context, pn *push.Notification[push.SubscriptionTopic]) error {
+	if err := c.failureOf(ctx, string(pn.Target)); err != nil {
+		return err
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.broadcasted = append(c.broadcasted, pn)
+
+	return nil
+}
+
+func (c *PushClient) BroadcastDelayed(ctx context.Context, pn *push.DelayedNotification) error {
+	if err := c.failureOf(ctx, string(pn.Target)); err != nil {
+		return err
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.broadcastedDelayed = append(c.broadcastedDelayed, pn)
+
+	return nil
+}
+
+func (*PushClient) Close() error {
+	return nil
+}
+
+// Sent returns the push notifications sent to device tokens, in the order they were sent.
+func (c *PushClient) Sent() []*push.Notification[push.DeviceToken] {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return append(make([]*push.Notification[push.DeviceToken], 0, len(c.sent)), c.sent...)
+}
+
+// Broadcasted returns the push notifications broadcasted to subscription topics, without a delay.
+func (c *PushClient) Broadcasted() []*push.Notification[push.SubscriptionTopic] {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return append(make([]*push.Notification[push.SubscriptionTopic], 0, len(c.broadcasted)), c.broadcasted...)
+}
+
+// BroadcastedDelayed returns the push notifications broadcasted to subscription topics, with a delay.
+func (c *PushClient) BroadcastedDelayed() []*push.DelayedNotification {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return append(make([]*push.DelayedNotification, 0, len(c.broadcastedDelayed)), c.broadcastedDelayed...)
+}
+
+// Reset forgets everything that was sent, but not the injected failures.
+func (c *PushClient) Reset() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.sent, c.broadcasted, c.broadcastedDelayed = nil, nil, nil
+}
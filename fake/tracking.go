@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package fake
+
+import (
+	"context"
+
+	"github.com/ice-blockchain/wintr/analytics/tracking"
+)
+
+func (c *TrackingClient) TrackAction(ctx context.Context, userID string, action *tracking.Action) error {
+	if err := c.failureOf(ctx, userID); err != nil {
+		return err
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.trackedActions = append(c.trackedActions, &TrackedAction{Action: action, UserID: userID})
+
+	return nil
+}
+
+func (c *TrackingClient) SetUserAttributes(ctx context.Context, userID string, attributes map[string]any) error {
+	if err := c.failureOf(ctx, userID); err != nil {
+		return err
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.setUserAttributes = append(c.setUserAttributes, &UserAttributes{Attributes: attributes, UserID: userID})
+
+	return nil
+}
+
+func (c *TrackingClient) DeleteUser(ctx context.Context, userID string) error {
+	if err := c.failureOf(ctx, userID); err != nil {
+		return err
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.deletedUsers = append(c.deletedUsers, userID)
+
+	return nil
+}
+
+// TrackedActions returns the actions tracked, in the order they were tracked.
+func (c *TrackingClient) TrackedActions() []*TrackedAction {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return append(make([]*TrackedAction, 0, len(c.trackedActions)), c.trackedActions...)
+}
+
+// SetUserAttributesCalls returns the user attributes set, in the order they were set.
+func (c *TrackingClient) SetUserAttributesCalls() []*UserAttributes {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return append(make([]*UserAttributes, 0, len(c.setUserAttributes)), c.setUserAttributes...)
+}
+
+// DeletedUsers returns the IDs of the users deleted, in the order they were deleted.
+func (c *TrackingClient) DeletedUsers() []string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return append(make([]string, 0, len(c.deletedUsers)), c.deletedUsers...)
+}
+
+// Reset forgets every call, but not the injected failures.
+func (c *TrackingClient) Reset() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.trackedActions, c.setUserAttributes, c.deletedUsers = nil, nil, nil
+}
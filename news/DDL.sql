@@ -16,6 +16,47 @@ CREATE TABLE IF NOT EXISTS news (
 CREATE INDEX IF NOT EXISTS most_recent_news_lookup_ix ON news (language, type, created_at DESC);
 ALTER TABLE news DROP CONSTRAINT IF EXISTS news_url_key;
 CREATE UNIQUE INDEX IF NOT EXISTS news_url_language_ix ON news (url,language);
+-- the url, as stored, is shown to the users, this is only used to detect duplicates; it's backfilled by the processor
+ALTER TABLE news ADD COLUMN IF NOT EXISTS canonical_url TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS news_canonical_url_language_ix ON news (canonical_url,language);
+-- pg_trgm is required only if news/duplicateTitleWindow is set; it's a deployment prerequisite if the service's role can't create extensions
+DO $$
+BEGIN
+    BEGIN
+        CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    EXCEPTION WHEN insufficient_privilege THEN
+        RAISE WARNING 'pg_trgm is missing and it can only be created by a privileged role: %', SQLERRM;
+    END;
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm') THEN
+        CREATE INDEX IF NOT EXISTS news_title_trgm_ix ON news USING GIN (title gin_trgm_ops);
+    END IF;
+END $$;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS targeting JSONB;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS variants JSONB;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS draft BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS priority BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS pinned_until TIMESTAMP;
+-- the views and reactions from before it are not attributed to any variant
+ALTER TABLE news ADD COLUMN IF NOT EXISTS experiment_started_at TIMESTAMP;
+UPDATE news SET experiment_started_at = updated_at WHERE variants IS NOT NULL AND experiment_started_at IS NULL;
+-- it pads to major.minor.patch, so that 1.2 == 1.2.0
+CREATE OR REPLACE FUNCTION news_app_version(version TEXT)
+RETURNS INT[] AS $$
+    SELECT CASE WHEN COALESCE(version, '') = '' THEN NULL
+                ELSE (string_to_array(version, '.')::INT[] || ARRAY[0,0,0])[1:3]
+           END
+$$ LANGUAGE SQL IMMUTABLE;
+-- has to be kept in sync with notifications.Targeting.Matches
+CREATE OR REPLACE FUNCTION news_targeting_matches(targeting JSONB, country TEXT, app_version TEXT, segments TEXT[])
+RETURNS BOOLEAN AS $$
+    SELECT targeting IS NULL
+        OR (
+                (jsonb_array_length(COALESCE(targeting->'countries', '[]'::JSONB)) = 0 OR targeting->'countries' ? upper(country))
+            AND (jsonb_array_length(COALESCE(targeting->'segments', '[]'::JSONB)) = 0 OR targeting->'segments' ?| segments)
+            AND (COALESCE(targeting->>'minAppVersion', '') = '' OR news_app_version(app_version) >= news_app_version(targeting->>'minAppVersion'))
+            AND (COALESCE(targeting->>'maxAppVersion', '') = '' OR news_app_version(app_version) <= news_app_version(targeting->>'maxAppVersion'))
+           ) IS TRUE
+$$ LANGUAGE SQL IMMUTABLE;
 -- news_viewed_by_users
 CREATE TABLE IF NOT EXISTS news_viewed_by_users (
                    created_at TIMESTAMP NOT NULL,
@@ -25,6 +66,10 @@ CREATE TABLE IF NOT EXISTS news_viewed_by_users (
                    PRIMARY KEY(language,news_id,user_id),
                    FOREIGN KEY(language,news_id) REFERENCES news(language,id) ON DELETE CASCADE
                    );
+ALTER TABLE news_viewed_by_users ADD COLUMN IF NOT EXISTS variant TEXT NOT NULL DEFAULT 'control';
+ALTER TABLE news_viewed_by_users ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'organic';
+CREATE INDEX IF NOT EXISTS news_viewed_by_users_news_id_created_at_ix ON news_viewed_by_users (news_id, created_at);
+CREATE INDEX IF NOT EXISTS news_viewed_by_users_user_id_ix ON news_viewed_by_users (user_id);
 -- news_tags
 CREATE TABLE IF NOT EXISTS news_tags  (
                    created_at TIMESTAMP NOT NULL,
@@ -42,6 +87,18 @@ CREATE TABLE IF NOT EXISTS news_tags_per_news  (
                    FOREIGN KEY(language, news_id) REFERENCES news(language,id) ON DELETE CASCADE,
                    FOREIGN KEY(language, news_tag) REFERENCES news_tags(language,value) ON DELETE CASCADE
                    );
+-- news_reactions_by_users
+CREATE TABLE IF NOT EXISTS news_reactions_by_users (
+                   created_at TIMESTAMP NOT NULL,
+                   language   TEXT NOT NULL,
+                   news_id    TEXT NOT NULL,
+                   user_id    TEXT NOT NULL,
+                   reaction   TEXT NOT NULL,
+                   PRIMARY KEY(language,news_id,user_id),
+                   FOREIGN KEY(language,news_id) REFERENCES news(language,id) ON DELETE CASCADE
+                   );
+CREATE INDEX IF NOT EXISTS news_reactions_by_users_news_id_ix ON news_reactions_by_users (news_id, reaction);
+ALTER TABLE news_reactions_by_users ADD COLUMN IF NOT EXISTS variant TEXT NOT NULL DEFAULT 'control';
 
 -- aggregated news views across languages
 CREATE MATERIALIZED VIEW IF NOT EXISTS news_views AS (
@@ -7,6 +7,7 @@ import (
 	_ "embed"
 	"io"
 	"mime/multipart"
+	stdlibtime "time"
 
 	"github.com/pkg/errors"
 
@@ -25,19 +26,45 @@ const (
 	FeaturedNewsType Type = "featured"
 )
 
+const (
+	LikeReaction Reaction = "like"
+)
+
+const (
+	PushViewSource    ViewSource = "push"
+	InAppViewSource   ViewSource = "inapp"
+	OrganicViewSource ViewSource = "organic"
+)
+
+const (
+	// ControlVariant is the original title/image of a news article, when it has Variants.
+	ControlVariant = "control"
+)
+
 var (
 	ErrNotFound              = storage.ErrNotFound
 	ErrDuplicate             = storage.ErrDuplicate
 	ErrRaceCondition         = errors.New("race condition")
 	ErrInvalidImageExtension = errors.New("invalid image extension")
+	ErrInvalidReaction       = errors.New("invalid reaction")
+
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	AllViewSources = users.Enum[ViewSource]{PushViewSource, InAppViewSource, OrganicViewSource}
 )
 
 type (
 	Type         = string
 	Tag          = string
 	Tags         = users.Enum[Tag]
+	Reaction     = string
+	ViewSource   = string
 	PersonalNews struct {
 		Viewed *bool `json:"viewed,omitempty" example:"true"`
+		// The reaction of the authorized user, if any.
+		Reaction  Reaction            `json:"reaction,omitempty" example:"like"`
+		Reactions map[Reaction]uint64 `json:"reactions,omitempty"`
+		// The variant the authorized user was bucketed into, if the news article has Variants.
+		Variant string `json:"variant,omitempty" example:"b"`
 		*News
 	}
 	TaggedNews struct {
@@ -48,13 +75,52 @@ type (
 		CreatedAt *time.Time `json:"createdAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
 		UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
 		*notifications.NotificationChannels
-		ID       string `json:"id,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
-		Type     Type   `json:"type,omitempty" example:"regular"`
-		Language string `json:"language,omitempty" example:"en"`
-		Title    string `json:"title,omitempty" example:"The importance of the blockchain technology"`
-		ImageURL string `json:"imageUrl,omitempty" example:"https://somewebsite.com/blockchain.jpg"`
-		URL      string `json:"url,omitempty" example:"https://somewebsite.com/blockchain"`
-		Views    uint64 `json:"views" example:"123"`
+		// If set, only the users matching it are going to see it/get notified about it.
+		Targeting *notifications.Targeting `json:"targeting,omitempty"`
+		// If set, users are split evenly and deterministically between the original title/image and each of these.
+		Variants *Variants `json:"variants,omitempty"`
+		// Drafts are not visible to users and nobody is notified about them until they're published.
+		Draft *bool `json:"draft,omitempty" example:"false"`
+		// Only for `featured` news. The ones with higher priorities are shown first.
+		Priority *int64 `json:"priority,omitempty" example:"10"`
+		// Only for `featured` news. Until then, it's shown before the other featured news, regardless of priority.
+		PinnedUntil *time.Time `json:"pinnedUntil,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		// When the current Variants were set. It's internal, only used for the experiment report.
+		ExperimentStartedAt *time.Time `json:"-"`
+		// The URL, as returned by CanonicalURL. It's internal, only used to detect duplicates.
+		CanonicalURL *string `json:"-"`
+		ID           string  `json:"id,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Type         Type    `json:"type,omitempty" example:"regular"`
+		Language     string  `json:"language,omitempty" example:"en"`
+		Title        string  `json:"title,omitempty" example:"The importance of the blockchain technology"`
+		ImageURL     string  `json:"imageUrl,omitempty" example:"https://somewebsite.com/blockchain.jpg"`
+		URL          string  `json:"url,omitempty" example:"https://somewebsite.com/blockchain"`
+		Views        uint64  `json:"views" example:"123"`
+	}
+	Variants = []*Variant
+	Variant  struct {
+		// Has to be unique per news article and different from `control`.
+		ID string `json:"id" example:"b"`
+		// Optional. If empty, the original title is used.
+		Title string `json:"title,omitempty" example:"Why blockchain matters"`
+		// Optional. If empty, the original image is used.
+		ImageURL string `json:"imageUrl,omitempty" example:"https://somewebsite.com/blockchain-b.jpg"`
+	}
+	ExperimentReport struct {
+		NewsID   string `json:"newsId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Language string `json:"language" example:"en"`
+		// When the current variants were set. Only the views and reactions after it are counted. Empty if there are no variants.
+		StartedAt *time.Time `json:"startedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		// The variant with the most views(ties are broken by reactions). Empty if there's no data yet.
+		Winner   string                    `json:"winner,omitempty" example:"b"`
+		Variants []*ExperimentVariantStats `json:"variants"`
+	}
+	ExperimentVariantStats struct {
+		Variant   string `json:"variant" example:"b"`
+		Title     string `json:"title" example:"Why blockchain matters"`
+		ImageURL  string `json:"imageUrl" example:"https://somewebsite.com/blockchain-b.jpg"`
+		Views     uint64 `json:"views" example:"123"`
+		Reactions uint64 `json:"reactions" example:"12"`
 	}
 	TaggedNewsSnapshot struct {
 		*TaggedNews
@@ -65,19 +131,68 @@ type (
 		NewsID    string     `json:"newsId" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
 		Language  string     `json:"language" example:"en"`
 		UserID    string     `json:"userId" example:"7bed2a2d-cb25-4b59-8e9b-93708630d8dc"`
+		Variant   string     `json:"variant,omitempty" example:"b"`
+		// How the user got to the news article.
+		Source ViewSource `json:"source,omitempty" example:"push"`
+	}
+	NewsReaction struct {
+		CreatedAt *time.Time `json:"createdAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		NewsID    string     `json:"newsId" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
+		Language  string     `json:"language" example:"en"`
+		UserID    string     `json:"userId" example:"7bed2a2d-cb25-4b59-8e9b-93708630d8dc"`
+		// Empty if the reaction was removed.
+		Reaction Reaction `json:"reaction,omitempty" example:"like"`
+		// Empty if the user had no reaction before.
+		PreviousReaction Reaction `json:"previousReaction,omitempty" example:"🔥"`
+		Variant          string   `json:"variant,omitempty" example:"b"`
 	}
 	UnreadNewsCount struct {
 		Count uint64 `json:"count" example:"1"`
 	}
+	DailyEngagement struct {
+		Day      *time.Time `json:"day" example:"2022-01-03T00:00:00Z"`
+		Language string     `json:"language" example:"en"`
+		// The users that viewed it that day for the first time. Only the first view of each user is recorded.
+		NewViewers   uint64 `json:"newViewers" example:"123"`
+		PushOpens    uint64 `json:"pushOpens" example:"50"`
+		InAppOpens   uint64 `json:"inAppOpens" example:"20"`
+		OrganicOpens uint64 `json:"organicOpens" example:"50"`
+	}
+	TranslationCoverage struct {
+		UpdatedAt *time.Time `json:"updatedAt" example:"2022-01-03T16:20:52.156534Z"`
+		ID        string     `json:"id" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Type      Type       `json:"type" example:"regular"`
+		Title     string     `json:"title" example:"The importance of the blockchain technology"`
+		// The languages the news article is translated into, including the stale ones.
+		Existing []string `json:"existing" example:"de,es"`
+		// The translations that were updated before the latest `en` update.
+		Stale []string `json:"stale" example:"es"`
+		// The expected languages the news article is not translated into yet.
+		Missing []string `json:"missing" example:"fr"`
+	}
 	ReadRepository interface {
 		GetNews(ctx context.Context, newsType Type, language string, limit, offset uint64, createdAfter *time.Time) ([]*PersonalNews, error)
 		GetUnreadNewsCount(ctx context.Context, language string, createdAfter *time.Time) (*UnreadNewsCount, error)
+		GetExperimentReport(ctx context.Context, newsID, language string) (*ExperimentReport, error)
+		// GetEngagement returns the daily engagement, per language, with a news article, in the [from,to) interval.
+		GetEngagement(ctx context.Context, newsID string, from, to *time.Time) ([]*DailyEngagement, error)
+		GetNewsFeed(ctx context.Context, language string, limit uint64) ([]*TaggedNews, error)
+		// GetFeaturedNews returns the published, untargeted featured `en` news articles, in the order they're shown to the users.
+		GetFeaturedNews(ctx context.Context) ([]*TaggedNews, error)
+		// GetTranslationCoverage reports, for each `en` news article, the translations it has and the ones it lacks.
+		// If languages is empty, every language that has at least one news article is expected.
+		GetTranslationCoverage(ctx context.Context, languages []string, limit, offset uint64) ([]*TranslationCoverage, error)
 	}
 	WriteRepository interface {
 		CreateNews(ctx context.Context, news []*TaggedNews, image *multipart.FileHeader) error
 		ModifyNews(ctx context.Context, news *TaggedNews, image *multipart.FileHeader) error
 		DeleteNews(ctx context.Context, newsID, language string) error
 		IncrementViews(ctx context.Context, newsID, language string) error
+		AddReaction(ctx context.Context, newsID, language string, reaction Reaction) error
+		RemoveReaction(ctx context.Context, newsID, language string) error
+		// ReorderFeaturedNews assigns descending priorities to the provided featured news, in the provided order,
+		// and resets the priority of the other published, untargeted featured news.
+		ReorderFeaturedNews(ctx context.Context, newsIDs []string) error
 	}
 	Repository interface {
 		io.Closer
@@ -95,8 +210,12 @@ type (
 
 const (
 	applicationYamlKey          = "news"
+	cacheApplicationYamlKey     = "news/cache"
 	requestingUserIDCtxValueKey = "requestingUserIDCtxValueKey"
 	checksumCtxValueKey         = "versioningChecksumCtxValueKey"
+	audienceCtxValueKey         = "audienceCtxValueKey"
+	viewSourceCtxValueKey       = "viewSourceCtxValueKey"
+	similarTitlesCtxValueKey    = "similarTitlesCtxValueKey"
 
 	fallbackLanguage = "en"
 )
@@ -115,6 +234,7 @@ type (
 		db            *storage.DB
 		mb            messagebroker.Client
 		pictureClient picture.Client
+		cache         *newsCache
 	}
 
 	processor struct {
@@ -123,6 +243,37 @@ type (
 	// | config holds the configuration of this package mounted from `application.yaml`.
 	config struct {
 		DeeplinkApp          string                   `yaml:"deeplinkApp"`
+		Reactions            []Reaction               `yaml:"reactions"`
+		FeedSources          []*feedSource            `yaml:"feedSources"`
 		messagebroker.Config `mapstructure:",squash"` //nolint:tagliatelle // Nope.
+		// The languages to fall back to, in order, when a news article is not translated into the key language.
+		// `en` is always the last resort.
+		FallbackLanguages   map[string][]string `yaml:"fallbackLanguages"`
+		FeedPollingInterval stdlibtime.Duration `yaml:"feedPollingInterval"`
+		// If set, the featured news with the same priority are rotated every period.
+		FeaturedRotationPeriod stdlibtime.Duration `yaml:"featuredRotationPeriod"`
+		// How many featured news are shown at most. Defaults to 1.
+		FeaturedSlots uint64 `yaml:"featuredSlots"`
+		// If set, the non-personal part of the news lists is cached, in memory, for this long, or until any news article changes.
+		// What the user viewed, reacted to and is targeted by is still queried on every request.
+		// Changes are consumed from the `news/cache` messageBroker config, without a consumer group, so that every instance gets them.
+		ListCacheTTL stdlibtime.Duration `yaml:"listCacheTTL"`
+		// If set, a news article is considered a duplicate of another one, in the same language, created within this window,
+		// if their titles are similar enough (see DuplicateTitleSimilarity).
+		// It requires the pg_trgm extension, which has to be created beforehand if the service's DB role lacks the privileges.
+		DuplicateTitleWindow stdlibtime.Duration `yaml:"duplicateTitleWindow"`
+		// The minimum share, between 0 and 1, of common words for two titles to be considered similar. Defaults to 0.8.
+		DuplicateTitleSimilarity float64 `yaml:"duplicateTitleSimilarity"`
+	}
+	// | feedSource is an external RSS/Atom feed that news articles are ingested from.
+	feedSource struct {
+		// The http(s) URL of the RSS 2.0 or Atom feed.
+		URL                  string                              `yaml:"url"`
+		Language             string                              `yaml:"language"`
+		Type                 Type                                `yaml:"type"`
+		Tags                 []Tag                               `yaml:"tags"`
+		NotificationChannels []notifications.NotificationChannel `yaml:"notificationChannels"`
+		// If false, the ingested news articles are created as drafts and have to be published manually.
+		Publish bool `yaml:"publish"`
 	}
 )
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/husky/notifications"
 	appcfg "github.com/ice-blockchain/wintr/config"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
@@ -29,13 +30,24 @@ func New(ctx context.Context, _ context.CancelFunc) Repository {
 	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
 
 	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
-
-	return &repository{
+	repo := &repository{
 		cfg:           &cfg,
 		shutdown:      db.Close,
 		db:            db,
 		pictureClient: picture.New(applicationYamlKey),
 	}
+	if cfg.ListCacheTTL > 0 {
+		repo.cache = newNewsCache(cfg.ListCacheTTL)
+		invalidator := mustStartNewsCacheInvalidator(repo.cache)
+		repo.shutdown = func() error {
+			return multierror.Append( //nolint:wrapcheck // .
+				errors.Wrap(invalidator.Close(), "closing news cache invalidator failed"),
+				errors.Wrap(db.Close(), "closing db connection failed"),
+			).ErrorOrNil()
+		}
+	}
+
+	return repo
 }
 
 func StartProcessor(ctx context.Context, _ context.CancelFunc) Processor {
@@ -53,7 +65,11 @@ func StartProcessor(ctx context.Context, _ context.CancelFunc) Processor {
 		pictureClient: picture.New(applicationYamlKey),
 	}}
 
+	go func() {
+		log.Error(errors.Wrap(prc.backfillCanonicalURLs(ctx), "failed to backfillCanonicalURLs"))
+	}()
 	go prc.startNewsViewsUpdater(ctx)
+	go prc.startFeedIngester(ctx)
 
 	return prc
 }
@@ -131,6 +147,53 @@ func ContextWithChecksum(ctx context.Context, checksum string) context.Context {
 	return context.WithValue(ctx, checksumCtxValueKey, checksum) //nolint:revive,staticcheck //.
 }
 
+// ContextWithAudience is required for the news targeting to be evaluated. Without it, targeted news are not visible.
+func ContextWithAudience(ctx context.Context, audience *notifications.Audience) context.Context {
+	if audience == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, audienceCtxValueKey, audience) //nolint:revive,staticcheck //.
+}
+
+// ContextWithViewSource is used to attribute news views to the notification channel the user came from.
+func ContextWithViewSource(ctx context.Context, source ViewSource) context.Context {
+	if source == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, viewSourceCtxValueKey, source) //nolint:revive,staticcheck //.
+}
+
+func viewSource(ctx context.Context) ViewSource {
+	if source, ok := ctx.Value(viewSourceCtxValueKey).(ViewSource); ok && source != "" {
+		return source
+	}
+
+	return OrganicViewSource
+}
+
+func audience(ctx context.Context) (country, appVersion string, segments []string) {
+	aud, ok := ctx.Value(audienceCtxValueKey).(*notifications.Audience)
+	if !ok || aud == nil {
+		return "", "", []string{}
+	}
+	segments = make([]string, 0, len(aud.Segments))
+	for _, segment := range aud.Segments {
+		segments = append(segments, string(segment))
+	}
+
+	return aud.Country, aud.AppVersion, segments
+}
+
+func targeting(t *notifications.Targeting) *notifications.Targeting {
+	if t.IsEmpty() {
+		return nil
+	}
+
+	return t
+}
+
 func (n *TaggedNews) Checksum() string {
 	if n.UpdatedAt == nil {
 		return ""
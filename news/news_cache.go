@@ -0,0 +1,369 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // It's not used for security, it just has to match the `md5` used in SQL.
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/ice-blockchain/husky/notifications"
+	appcfg "github.com/ice-blockchain/wintr/config"
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+type (
+	// | newsCache holds, per language and type, every visible news article, without anything personal about them.
+	newsCache struct {
+		mx      *sync.RWMutex
+		entries map[newsCacheKey]*newsCacheEntry
+		ttl     stdlibtime.Duration
+	}
+	newsCacheKey struct {
+		Language string
+		Type     Type
+	}
+	newsCacheEntry struct {
+		loadedAt *time.Time
+		news     []*PersonalNews
+	}
+	// | newsCacheInvalidator clears the newsCache whenever any news article changes.
+	// It consumes without a consumer group, so that every instance gets every change and invalidates its own cache.
+	newsCacheInvalidator struct {
+		cache  *newsCache
+		client *kgo.Client
+	}
+	// | viewedNewsLanguages is, per news ID, the languages the user viewed it in.
+	viewedNewsLanguages map[string]map[string]struct{}
+)
+
+func newNewsCache(ttl stdlibtime.Duration) *newsCache {
+	return &newsCache{
+		mx:      new(sync.RWMutex),
+		entries: make(map[newsCacheKey]*newsCacheEntry),
+		ttl:     ttl,
+	}
+}
+
+// mustStartNewsCacheInvalidator consumes the `consumingTopics` of the `news/cache` messageBroker config, from their end.
+func mustStartNewsCacheInvalidator(cache *newsCache) *newsCacheInvalidator {
+	var cfg messagebroker.Config
+	appcfg.MustLoadFromKey(cacheApplicationYamlKey, &cfg)
+	topics := make([]string, 0, len(cfg.MessageBroker.ConsumingTopics))
+	for _, topic := range cfg.MessageBroker.ConsumingTopics {
+		topics = append(topics, topic.Name)
+	}
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.MessageBroker.URLs...),
+		kgo.ConsumeTopics(topics...),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtEnd()),
+	}
+	if cfg.MessageBroker.CertPath != "" {
+		tlsConfig, err := newsCacheMessageBrokerTLS(&cfg)
+		log.Panic(errors.Wrap(err, "failed to build the news cache message broker TLS")) //nolint:revive // That's intended.
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+	client, err := kgo.NewClient(opts...)
+	log.Panic(errors.Wrap(err, "failed to connect to the news cache message broker")) //nolint:revive // That's intended.
+	inv := &newsCacheInvalidator{cache: cache, client: client}
+	go inv.startConsuming()
+
+	return inv
+}
+
+func newsCacheMessageBrokerTLS(cfg *messagebroker.Config) (*tls.Config, error) {
+	caCert, err := os.ReadFile(cfg.MessageBroker.CertPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v", cfg.MessageBroker.CertPath)
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, errors.Errorf("failed to AppendCertsFromPEM %v", cfg.MessageBroker.CertPath)
+	}
+	var accessCerts []tls.Certificate
+	if cfg.MessageBroker.AccessKeyPath != "" && cfg.MessageBroker.AccessCertPath != "" {
+		keypair, lErr := tls.LoadX509KeyPair(cfg.MessageBroker.AccessCertPath, cfg.MessageBroker.AccessKeyPath)
+		if lErr != nil {
+			return nil, errors.Wrapf(lErr, "failed to load access (key,cert) pair at (`%v`,`%v`)",
+				cfg.MessageBroker.AccessKeyPath, cfg.MessageBroker.AccessCertPath)
+		}
+		accessCerts = []tls.Certificate{keypair}
+	}
+
+	return &tls.Config{MinVersion: tls.VersionTLS13, Certificates: accessCerts, RootCAs: caCertPool}, nil
+}
+
+// startConsuming clears the cache once per poll, if there were any changes. It stops when the invalidator is closed.
+func (inv *newsCacheInvalidator) startConsuming() {
+	for {
+		fetches := inv.client.PollFetches(context.Background())
+		if fetches.IsClientClosed() {
+			return
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			log.Error(errors.Wrapf(err, "failed to fetch %v[%v] for the news cache", topic, partition))
+		})
+		if fetches.NumRecords() != 0 {
+			inv.cache.clear()
+		}
+	}
+}
+
+func (inv *newsCacheInvalidator) Close() error {
+	inv.client.Close()
+
+	return nil
+}
+
+func (c *newsCache) clear() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.entries = make(map[newsCacheKey]*newsCacheEntry, len(c.entries))
+}
+
+func (c *newsCache) get(key newsCacheKey, now *time.Time) ([]*PersonalNews, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	if entry, found := c.entries[key]; found && now.Sub(*entry.loadedAt.Time) < c.ttl {
+		return entry.news, true
+	}
+
+	return nil, false
+}
+
+func (c *newsCache) set(key newsCacheKey, news []*PersonalNews, now *time.Time) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.entries[key] = &newsCacheEntry{loadedAt: now, news: news}
+}
+
+func (r *repository) getCachedNews(
+	ctx context.Context, newsType Type, language string, limit, offset uint64, createdAfter *time.Time,
+) ([]*PersonalNews, error) {
+	now := time.Now()
+	visible, viewed, err := r.visibleCachedNews(ctx, newsType, language, now)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get visible cached news for (%v,%v)", newsType, language)
+	}
+	if newsType == FeaturedNewsType {
+		if offset >= r.cfg.featuredSlots() {
+			return []*PersonalNews{}, nil
+		}
+		if limit > r.cfg.featuredSlots()-offset {
+			limit = r.cfg.featuredSlots() - offset
+		}
+		r.cfg.sortFeaturedNews(visible, now)
+	} else {
+		sort.SliceStable(visible, func(i, j int) bool {
+			iUnread := !viewed.viewed(visible[i]) || !visible[i].CreatedAt.Before(*createdAfter.Time)
+			jUnread := !viewed.viewed(visible[j]) || !visible[j].CreatedAt.Before(*createdAfter.Time)
+			if iUnread != jUnread {
+				return iUnread
+			}
+
+			return visible[i].CreatedAt.After(*visible[j].CreatedAt.Time)
+		})
+	}
+	if offset >= uint64(len(visible)) {
+		return []*PersonalNews{}, nil
+	}
+	visible = visible[offset:]
+	if limit < uint64(len(visible)) {
+		visible = visible[:limit]
+	}
+	result := make([]*PersonalNews, 0, len(visible))
+	for _, cached := range visible {
+		nws := *cached.News
+		nws.Targeting, nws.Priority, nws.PinnedUntil = nil, nil, nil
+		elem := &PersonalNews{News: &nws}
+		isViewed := viewed.viewed(cached) || elem.CreatedAt.Before(*createdAfter.Time)
+		elem.Viewed = &isViewed
+		elem.applyVariant(requestingUserID(ctx))
+		result = append(result, elem)
+	}
+	if err = r.enhanceWithReactions(ctx, result); err != nil {
+		return nil, errors.Wrapf(err, "failed to enhanceWithReactions for (%v,%v)", newsType, language)
+	}
+
+	return result, nil
+}
+
+func (r *repository) getCachedUnreadNewsCount(ctx context.Context, language string, createdAfter *time.Time) (*UnreadNewsCount, error) {
+	now := time.Now()
+	result := new(UnreadNewsCount)
+	for _, newsType := range []Type{RegularNewsType, FeaturedNewsType} {
+		visible, viewed, err := r.visibleCachedNews(ctx, newsType, language, now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get visible cached news for (%v,%v)", newsType, language)
+		}
+		if newsType == FeaturedNewsType {
+			r.cfg.sortFeaturedNews(visible, now)
+			if uint64(len(visible)) > r.cfg.featuredSlots() {
+				visible = visible[:r.cfg.featuredSlots()]
+			}
+		}
+		for _, nws := range visible {
+			if !viewed.viewed(nws) && !nws.CreatedAt.Before(*createdAfter.Time) {
+				result.Count++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// visibleCachedNews returns the cached news that the user is allowed to see (the slice is a copy, but the elements are not),
+// and the news the user already viewed. The latter is personal, so it is never cached.
+func (r *repository) visibleCachedNews(
+	ctx context.Context, newsType Type, language string, now *time.Time,
+) ([]*PersonalNews, viewedNewsLanguages, error) {
+	key := newsCacheKey{Language: language, Type: newsType}
+	all, found := r.cache.get(key, now)
+	if !found {
+		var err error
+		if all, err = r.loadNonPersonalNews(ctx, newsType, language); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to loadNonPersonalNews for %#v", key)
+		}
+		r.cache.set(key, all, now)
+	}
+	aud, _ := ctx.Value(audienceCtxValueKey).(*notifications.Audience) //nolint:errcheck,revive // Not needed.
+	visible := make([]*PersonalNews, 0, len(all))
+	for _, nws := range all {
+		if nws.Targeting.Matches(aud) {
+			visible = append(visible, nws)
+		}
+	}
+	newsIDs := make([]string, 0, len(visible))
+	for _, nws := range visible {
+		newsIDs = append(newsIDs, nws.ID)
+	}
+	viewed, err := r.getViewedNewsLanguages(ctx, newsIDs)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to getViewedNewsLanguages")
+	}
+
+	return visible, viewed, nil
+}
+
+// loadNonPersonalNews has to be kept in sync with GetNews.
+func (r *repository) loadNonPersonalNews(ctx context.Context, newsType Type, language string) ([]*PersonalNews, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := fmt.Sprintf(`SELECT COALESCE(n_en.created_at,n.created_at) AS created_at,
+							COALESCE(n.updated_at, n_en.updated_at) AS updated_at,
+							COALESCE(v.views,v_en.views) as views,
+							COALESCE(n.id,n_en.id) AS id,
+							COALESCE(n.type, n_en.type) AS type,
+							COALESCE(n.language, n_en.language) AS language,
+							COALESCE(n.title, n_en.title) AS title,
+							COALESCE(n.image_url, n_en.image_url) AS image_url,
+							COALESCE(n.url, n_en.url) AS url,
+							(CASE WHEN n.id IS NOT NULL THEN n.variants ELSE n_en.variants END) AS variants,
+							COALESCE(n.targeting, n_en.targeting) AS targeting,
+							n_en.priority,
+							n_en.pinned_until
+			FROM news n_en
+				LEFT JOIN LATERAL (SELECT *
+								   FROM news
+								   WHERE id = n_en.id
+									 AND language = ANY($1)
+									 AND draft = FALSE
+								   ORDER BY array_position($1, language)
+								   LIMIT 1) n ON TRUE
+				LEFT JOIN news_views v ON v.id = n.id
+				LEFT JOIN news_views v_en ON v_en.id = n_en.id
+			WHERE n_en.language = '%[1]v'
+				  AND n_en.type = $2
+				  AND n_en.draft = FALSE
+			ORDER BY n_en.created_at DESC`, fallbackLanguage)
+	result, err := storage.Select[PersonalNews](ctx, r.db, sql, r.cfg.fallbackChain(language), newsType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select non personal news for (%v,%v)", newsType, language)
+	}
+	for _, elem := range result {
+		elem.UpdatedAt = nil
+		elem.ImageURL = r.pictureClient.DownloadURL(elem.ImageURL)
+	}
+
+	return result, nil
+}
+
+// getViewedNewsLanguages is limited to the provided news, so that it doesn't grow with the view history of the user.
+func (r *repository) getViewedNewsLanguages(ctx context.Context, newsIDs []string) (viewedNewsLanguages, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	type viewedNews struct {
+		NewsID   string
+		Language string
+	}
+	if len(newsIDs) == 0 {
+		return viewedNewsLanguages{}, nil
+	}
+	sql := `SELECT news_id, language FROM news_viewed_by_users WHERE user_id = $1 AND news_id = ANY($2)`
+	result, err := storage.Select[viewedNews](ctx, r.db, sql, requestingUserID(ctx), newsIDs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select viewed news for userID:%v", requestingUserID(ctx))
+	}
+	viewed := make(viewedNewsLanguages, len(result))
+	for _, elem := range result {
+		if viewed[elem.NewsID] == nil {
+			viewed[elem.NewsID] = make(map[string]struct{}, 1)
+		}
+		viewed[elem.NewsID][elem.Language] = struct{}{}
+	}
+
+	return viewed, nil
+}
+
+// viewed mirrors the `nvu`/`nvu_en` joins of GetNews.
+func (v viewedNewsLanguages) viewed(nws *PersonalNews) bool {
+	languages := v[nws.ID]
+	_, viewedInLanguage := languages[nws.Language]
+	_, viewedInFallbackLanguage := languages[fallbackLanguage]
+
+	return viewedInLanguage || viewedInFallbackLanguage
+}
+
+// sortFeaturedNews has to be kept in sync with featuredNewsOrder.
+func (cfg *config) sortFeaturedNews(news []*PersonalNews, now *time.Time) {
+	rotation := cfg.featuredRotation(now)
+	rotationKey := func(nws *PersonalNews) string {
+		if rotation == "" {
+			return ""
+		}
+		hash := md5.Sum([]byte(nws.ID + rotation)) //nolint:gosec // Check the import.
+
+		return hex.EncodeToString(hash[:])
+	}
+	pinned := func(nws *PersonalNews) bool {
+		return nws.PinnedUntil != nil && nws.PinnedUntil.Time != nil && nws.PinnedUntil.After(*now.Time)
+	}
+	sort.SliceStable(news, func(i, j int) bool {
+		if iPinned, jPinned := pinned(news[i]), pinned(news[j]); iPinned != jPinned {
+			return iPinned
+		}
+		if iPriority, jPriority := priority(news[i].Priority), priority(news[j].Priority); iPriority != jPriority {
+			return iPriority > jPriority
+		}
+		if iKey, jKey := rotationKey(news[i]), rotationKey(news[j]); iKey != jKey {
+			return iKey < jKey
+		}
+
+		return news[i].CreatedAt.After(*news[j].CreatedAt.Time)
+	})
+}
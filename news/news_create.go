@@ -19,6 +19,9 @@ func (r *repository) CreateNews(ctx context.Context, news []*TaggedNews, image *
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "context failed")
 	}
+	if err := r.detectDuplicates(ctx, news...); err != nil {
+		return errors.Wrapf(err, "failed to detectDuplicates for news:%#v", news)
+	}
 	id, now := uuid.NewString(), time.Now()
 	if err := r.validateAndUploadImage(ctx, image, id, now); err != nil {
 		return errors.Wrapf(err, "failed to validateAndUploadImage for news:%#v", news)
@@ -48,17 +51,18 @@ func (r *repository) insertNews(ctx context.Context, news []*TaggedNews) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "context failed")
 	}
-	const fields = 9
+	const fields = 16
 	args := make([]any, 0, len(news)*fields)
 	values := make([]string, 0, len(news))
 	for ix, nws := range news {
 		args = append(args, nws.CreatedAt.Time, nws.UpdatedAt.Time, nws.NotificationChannels.NotificationChannels, nws.ID, nws.Type, nws.Language,
-			nws.Title, nws.ImageURL, nws.URL,
+			nws.Title, nws.ImageURL, nws.URL, targeting(nws.Targeting), variants(nws.Variants), nws.Draft != nil && *nws.Draft,
+			priority(nws.Priority), pinnedUntil(nws.PinnedUntil), experimentStartedAt(nws.Variants, nws.CreatedAt), CanonicalURL(nws.URL),
 		)
-		values = append(values, fmt.Sprintf("($%[1]v,$%[2]v,$%[3]v,$%[4]v,$%[5]v,$%[6]v,$%[7]v,$%[8]v,$%[9]v)",
-			fields*ix+1, fields*ix+2, fields*ix+3, fields*ix+4, fields*ix+5, fields*ix+6, fields*ix+7, fields*ix+8, fields*ix+9)) //nolint:gomnd // .
+		values = append(values, fmt.Sprintf("($%[1]v,$%[2]v,$%[3]v,$%[4]v,$%[5]v,$%[6]v,$%[7]v,$%[8]v,$%[9]v,$%[10]v,$%[11]v,$%[12]v,$%[13]v,$%[14]v,$%[15]v,$%[16]v)",
+			fields*ix+1, fields*ix+2, fields*ix+3, fields*ix+4, fields*ix+5, fields*ix+6, fields*ix+7, fields*ix+8, fields*ix+9, fields*ix+10, fields*ix+11, fields*ix+12, fields*ix+13, fields*ix+14, fields*ix+15, fields*ix+16)) //nolint:gomnd,lll // .
 	}
-	sql := fmt.Sprintf(`INSERT INTO news (CREATED_AT, UPDATED_AT, NOTIFICATION_CHANNELS, ID, TYPE, LANGUAGE, TITLE, IMAGE_URL, URL) VALUES %v`, strings.Join(values, ",")) //nolint:lll // .
+	sql := fmt.Sprintf(`INSERT INTO news (CREATED_AT, UPDATED_AT, NOTIFICATION_CHANNELS, ID, TYPE, LANGUAGE, TITLE, IMAGE_URL, URL, TARGETING, VARIANTS, DRAFT, PRIORITY, PINNED_UNTIL, EXPERIMENT_STARTED_AT, CANONICAL_URL) VALUES %v`, strings.Join(values, ",")) //nolint:lll // .
 	if _, err := storage.Exec(ctx, r.db, sql, args...); err != nil {
 		return errors.Wrapf(detectAndParseDuplicateDatabaseError(err), "failed to insert news %#v", news)
 	}
@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/terror"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	defaultDuplicateTitleSimilarity = 0.8
+	similarTitleCandidatesLimit     = 10
+	canonicalURLBackfillBatchSize   = 1000
+)
+
+//nolint:gochecknoglobals // It's a static list.
+var trackingQueryParams = map[string]struct{}{
+	"fbclid":  {},
+	"gclid":   {},
+	"dclid":   {},
+	"msclkid": {},
+	"yclid":   {},
+	"igshid":  {},
+	"mc_cid":  {},
+	"mc_eid":  {},
+	"_hsenc":  {},
+	"_hsmi":   {},
+	"ref":     {},
+	"ref_src": {},
+}
+
+// CanonicalURL normalizes a news article URL, so that the same story can't be posted twice just because
+// of tracking parameters, http vs https, a `www.` prefix, a fragment or a trailing slash.
+// It's only used to detect duplicates; the URL shown to the users is stored as is.
+// If the URL can't be parsed, it's returned as is.
+func CanonicalURL(rawURL string) string {
+	trimmed := strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(trimmed)
+	if err != nil || parsed.Host == "" {
+		return trimmed
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if parsed.Scheme == "http" {
+		parsed.Scheme = "https"
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if port := parsed.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	parsed.Host = host
+	parsed.User, parsed.Fragment, parsed.RawFragment = nil, "", ""
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+	parsed.RawPath = strings.TrimRight(parsed.RawPath, "/")
+	query := parsed.Query()
+	for param := range query {
+		if _, tracking := trackingQueryParams[strings.ToLower(param)]; tracking || strings.HasPrefix(strings.ToLower(param), "utm_") {
+			query.Del(param)
+		}
+	}
+	parsed.RawQuery = query.Encode() // It's sorted by key.
+	parsed.ForceQuery = false
+
+	return parsed.String()
+}
+
+// ContextWithSimilarTitlesAllowed disables the near-duplicate title detection, for when editors knowingly post similar news.
+func ContextWithSimilarTitlesAllowed(ctx context.Context, allowed bool) context.Context {
+	if !allowed {
+		return ctx
+	}
+
+	return context.WithValue(ctx, similarTitlesCtxValueKey, allowed) //nolint:revive,staticcheck //.
+}
+
+func similarTitlesAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(similarTitlesCtxValueKey).(bool) //nolint:errcheck,revive // Not needed.
+
+	return allowed
+}
+
+// detectDuplicates returns a storage.ErrDuplicate terror, with the conflicting news article in its data, if there's another news article,
+// in the same language, with the same (canonical) url or with a similar title, created within the configured window.
+func (r *repository) detectDuplicates(ctx context.Context, news ...*TaggedNews) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	for _, nws := range news {
+		if nws.URL != "" {
+			duplicate, err := r.getNewsByURL(ctx, nws.ID, CanonicalURL(nws.URL), nws.Language)
+			if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+				return errors.Wrapf(err, "failed to getNewsByURL for %#v", nws)
+			}
+			if duplicate != nil {
+				return r.duplicateNewsError("url", duplicate)
+			}
+		}
+		if nws.Title == "" || r.cfg.DuplicateTitleWindow <= 0 || similarTitlesAllowed(ctx) {
+			continue
+		}
+		duplicate, err := r.getNewsWithSimilarTitle(ctx, nws.ID, nws.Title, nws.Language)
+		if err != nil {
+			return errors.Wrapf(err, "failed to getNewsWithSimilarTitle for %#v", nws)
+		}
+		if duplicate != nil {
+			return r.duplicateNewsError("title", duplicate)
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) duplicateNewsError(field string, duplicate *TaggedNews) error {
+	duplicate.ImageURL = r.pictureClient.DownloadURL(duplicate.ImageURL)
+
+	return terror.New(storage.ErrDuplicate, map[string]any{"field": field, "news": duplicate})
+}
+
+func (r *repository) getNewsByURL(ctx context.Context, excludedNewsID, canonicalURL, language string) (*TaggedNews, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT array_agg(t.news_tag ORDER BY t.created_at) filter (where t.news_tag is not null) AS tags,
+				   n.*
+			FROM news n
+				  LEFT JOIN news_tags_per_news t
+						 ON t.language = n.language
+						AND t.news_id  = n.id
+			WHERE n.language = $1
+			  AND n.canonical_url = $2
+			  AND n.id != $3
+			GROUP BY n.language, n.id
+			LIMIT 1`
+	result, err := storage.Get[TaggedNews](ctx, r.db, sql, language, canonicalURL, excludedNewsID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select news article by (canonicalURL:%v,language:%v)", canonicalURL, language)
+	}
+
+	return result, nil
+}
+
+// getNewsWithSimilarTitle narrows the candidates down to the most trigram-similar titles (see `news_title_trgm_ix`),
+// and only then checks them against the configured word similarity.
+func (r *repository) getNewsWithSimilarTitle(ctx context.Context, excludedNewsID, title, language string) (*TaggedNews, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT array_agg(t.news_tag ORDER BY t.created_at) filter (where t.news_tag is not null) AS tags,
+				   n.*
+			FROM news n
+				  LEFT JOIN news_tags_per_news t
+						 ON t.language = n.language
+						AND t.news_id  = n.id
+			WHERE n.language = $1
+			  AND n.created_at >= $2
+			  AND n.id != $3
+			  AND n.title % $4
+			GROUP BY n.language, n.id
+			ORDER BY similarity(n.title, $4) DESC, n.created_at DESC
+			LIMIT $5`
+	createdAfter := time.Now().Add(-r.cfg.DuplicateTitleWindow)
+	candidates, err := storage.Select[TaggedNews](ctx, r.db, sql, language, createdAfter, excludedNewsID, title, similarTitleCandidatesLimit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select news created after %v, for language %v", createdAfter, language)
+	}
+	words := titleWords(title)
+	for _, candidate := range candidates {
+		if titleSimilarity(words, titleWords(candidate.Title)) >= r.cfg.duplicateTitleSimilarity() {
+			return candidate, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil // Nil, nil is ok, because it means there's no similar news.
+}
+
+func (cfg *config) duplicateTitleSimilarity() float64 {
+	if cfg.DuplicateTitleSimilarity <= 0 || cfg.DuplicateTitleSimilarity > 1 {
+		return defaultDuplicateTitleSimilarity
+	}
+
+	return cfg.DuplicateTitleSimilarity
+}
+
+// titleWords returns the distinct, lowercase, words of a title, ignoring punctuation.
+func titleWords(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	sort.Strings(words)
+	distinct := words[:0]
+	for _, word := range words {
+		if len(distinct) == 0 || word != distinct[len(distinct)-1] {
+			distinct = append(distinct, word)
+		}
+	}
+
+	return distinct
+}
+
+// titleSimilarity is the Jaccard index of the words of two titles, as returned by titleWords.
+func titleSimilarity(words, otherWords []string) float64 {
+	if len(words) == 0 || len(otherWords) == 0 {
+		return 0
+	}
+	var common, ix, jx int
+	for ix < len(words) && jx < len(otherWords) {
+		switch {
+		case words[ix] == otherWords[jx]:
+			common++
+			ix++
+			jx++
+		case words[ix] < otherWords[jx]:
+			ix++
+		default:
+			jx++
+		}
+	}
+
+	return float64(common) / float64(len(words)+len(otherWords)-common)
+}
+
+// backfillCanonicalURLs sets the canonical_url of the news articles created before it existed.
+// If the canonical URL is already taken by another article, in the same language, it's left empty, cuz they're already duplicates.
+func (p *processor) backfillCanonicalURLs(ctx context.Context) error {
+	type newsURL struct {
+		ID       string
+		Language string
+		URL      string
+	}
+	var lastLanguage, lastID string
+	for ctx.Err() == nil {
+		sql := `SELECT id, language, url
+				FROM news
+				WHERE canonical_url IS NULL
+				  AND (language, id) > ($1, $2)
+				ORDER BY language, id
+				LIMIT $3`
+		batch, err := storage.Select[newsURL](ctx, p.db, sql, lastLanguage, lastID, canonicalURLBackfillBatchSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to select news without canonical url after (%v,%v)", lastLanguage, lastID)
+		}
+		for _, nws := range batch {
+			sql = `UPDATE news
+				   SET canonical_url = $3
+				   WHERE language = $1
+					 AND id = $2
+					 AND canonical_url IS NULL
+					 AND NOT EXISTS (SELECT 1 FROM news WHERE language = $1 AND canonical_url = $3)`
+			if _, err = storage.Exec(ctx, p.db, sql, nws.Language, nws.ID, CanonicalURL(nws.URL)); err != nil &&
+				!storage.IsErr(err, storage.ErrDuplicate) {
+				return errors.Wrapf(err, "failed to set canonical url for %#v", nws)
+			}
+			lastLanguage, lastID = nws.Language, nws.ID
+		}
+		if len(batch) < canonicalURLBackfillBatchSize {
+			return nil
+		}
+	}
+
+	return errors.Wrap(ctx.Err(), "context failed")
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetEngagement(ctx context.Context, newsID string, from, to *time.Time) ([]*DailyEngagement, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT date_trunc('day', created_at) AS day,
+				   language,
+				   COUNT(1) AS new_viewers,
+				   COUNT(1) FILTER (WHERE source = $4) AS push_opens,
+				   COUNT(1) FILTER (WHERE source = $5) AS in_app_opens,
+				   COUNT(1) FILTER (WHERE source = $6) AS organic_opens
+			FROM news_viewed_by_users
+			WHERE news_id = $1
+			  AND created_at >= $2
+			  AND created_at < $3
+			GROUP BY 1, 2
+			ORDER BY 1, 2`
+	args := []any{newsID, from.Time, to.Time, PushViewSource, InAppViewSource, OrganicViewSource}
+	result, err := storage.Select[DailyEngagement](ctx, r.db, sql, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select engagement for args:%#v", args...)
+	}
+
+	return result, nil
+}
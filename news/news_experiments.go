@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"context"
+	"hash/fnv"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetExperimentReport(ctx context.Context, newsID, language string) (*ExperimentReport, error) { //nolint:funlen // .
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	nws, err := r.getNewsByPK(ctx, newsID, language)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to getNewsByPK(%v,%v)", newsID, language)
+	}
+	type variantCount struct {
+		Variant   string
+		Views     uint64
+		Reactions uint64
+	}
+	startedAt := nws.ExperimentStartedAt
+	var counts []*variantCount
+	if startedAt != nil {
+		// The views and reactions from before the experiment started are not attributed to any variant, so they're excluded.
+		sql := `SELECT COALESCE(v.variant, rc.variant) AS variant,
+					   COALESCE(v.views, 0) AS views,
+					   COALESCE(rc.reactions, 0) AS reactions
+				FROM (SELECT variant, COUNT(1) AS views
+					  FROM news_viewed_by_users
+					  WHERE language = $1
+						AND news_id = $2
+						AND created_at >= $3
+					  GROUP BY variant) v
+					FULL OUTER JOIN (SELECT variant, COUNT(1) AS reactions
+									 FROM news_reactions_by_users
+									 WHERE language = $1
+									   AND news_id = $2
+									   AND created_at >= $3
+									 GROUP BY variant) rc
+								 ON rc.variant = v.variant`
+		if counts, err = storage.Select[variantCount](ctx, r.db, sql, language, newsID, startedAt.Time); err != nil {
+			return nil, errors.Wrapf(err, "failed to select experiment counts for (%v,%v)", newsID, language)
+		}
+	}
+	countsByVariant := make(map[string]*variantCount, len(counts))
+	for _, count := range counts {
+		countsByVariant[count.Variant] = count
+	}
+	report := &ExperimentReport{
+		StartedAt: startedAt,
+		NewsID:    newsID,
+		Language:  language,
+		Variants: []*ExperimentVariantStats{{
+			Variant:  ControlVariant,
+			Title:    nws.Title,
+			ImageURL: r.pictureClient.DownloadURL(nws.ImageURL),
+		}},
+	}
+	if nws.Variants != nil {
+		for _, variant := range *nws.Variants {
+			stats := &ExperimentVariantStats{Variant: variant.ID, Title: nws.Title, ImageURL: report.Variants[0].ImageURL}
+			stats.Title = mergeStringField(stats.Title, variant.Title)
+			stats.ImageURL = mergeStringField(stats.ImageURL, variant.ImageURL)
+			report.Variants = append(report.Variants, stats)
+		}
+	}
+	var winner *ExperimentVariantStats
+	for _, stats := range report.Variants {
+		if count, found := countsByVariant[stats.Variant]; found {
+			stats.Views, stats.Reactions = count.Views, count.Reactions
+		}
+		if (stats.Views != 0 || stats.Reactions != 0) &&
+			(winner == nil || stats.Views > winner.Views || (stats.Views == winner.Views && stats.Reactions > winner.Reactions)) {
+			winner = stats
+		}
+	}
+	if winner != nil {
+		report.Winner = winner.Variant
+	}
+
+	return report, nil
+}
+
+func (r *repository) getVariant(ctx context.Context, newsID, language, userID string) (string, error) {
+	if ctx.Err() != nil {
+		return "", errors.Wrap(ctx.Err(), "context failed")
+	}
+	type newsVariants struct {
+		Variants *Variants
+	}
+	sql := `SELECT variants FROM news WHERE language = $1 AND id = $2`
+	resp, err := storage.Get[newsVariants](ctx, r.db, sql, language, newsID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to select variants for (%v,%v)", newsID, language)
+	}
+
+	return bucket(resp.Variants, newsID, userID).ID, nil
+}
+
+// bucket deterministically assigns the user to the original (control) or to one of the variants.
+func bucket(variants *Variants, newsID, userID string) *Variant {
+	control := &Variant{ID: ControlVariant}
+	if variants == nil || len(*variants) == 0 {
+		return control
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(userID + "~~~" + newsID)) //nolint:errcheck // It never fails.
+	ix := hash.Sum32() % uint32(len(*variants)+1)
+	if ix == 0 {
+		return control
+	}
+
+	return (*variants)[ix-1]
+}
+
+func (n *PersonalNews) applyVariant(userID string) {
+	if n.Variants == nil || len(*n.Variants) == 0 {
+		n.Variants = nil
+
+		return
+	}
+	variant := bucket(n.Variants, n.ID, userID)
+	n.Variant = variant.ID
+	n.Title = mergeStringField(n.Title, variant.Title)
+	n.ImageURL = mergeStringField(n.ImageURL, variant.ImageURL)
+	n.Variants = nil
+}
+
+func experimentStartedAt(v *Variants, createdAt *time.Time) *stdlibtime.Time {
+	if variants(v) == nil {
+		return nil
+	}
+
+	return createdAt.Time
+}
+
+func variants(v *Variants) *Variants {
+	if v == nil || len(*v) == 0 {
+		return nil
+	}
+
+	return v
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"context"
+	"fmt"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetFeaturedNews(ctx context.Context) ([]*TaggedNews, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := fmt.Sprintf(`SELECT array_agg(t.news_tag ORDER BY t.created_at) filter (where t.news_tag is not null) AS tags,
+							   n_en.*
+						FROM news n_en
+							  LEFT JOIN news_tags_per_news t
+									 ON t.language = n_en.language
+									AND t.news_id  = n_en.id
+						WHERE n_en.language = $1
+						  AND n_en.type = $2
+						  AND n_en.draft = FALSE
+						  AND n_en.targeting IS NULL
+						GROUP BY n_en.language, n_en.id
+						ORDER BY %v`, featuredNewsOrder(3, 4)) //nolint:gomnd // Arg positions.
+	now := time.Now()
+	result, err := storage.Select[TaggedNews](ctx, r.db, sql, fallbackLanguage, FeaturedNewsType, now.Time, r.cfg.featuredRotation(now))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select featured news")
+	}
+	for _, elem := range result {
+		elem.ImageURL = r.pictureClient.DownloadURL(elem.ImageURL)
+	}
+
+	return result, nil
+}
+
+func (r *repository) ReorderFeaturedNews(ctx context.Context, newsIDs []string) error { //nolint:funlen // .
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	type (
+		featuredNews struct {
+			ID string
+		}
+		reorderedNews struct {
+			PreviousUpdatedAt *time.Time
+			*News
+			PreviousPriority int64
+		}
+	)
+	var reordered []*reorderedNews
+	now := time.Now()
+	err := storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		// The rows are locked, so that they can't stop being featured news until they're reordered.
+		sql := `SELECT id
+				FROM news
+				WHERE language = $1
+				  AND type = $2
+				  AND draft = FALSE
+				  AND targeting IS NULL
+				  AND id = ANY($3)
+				FOR UPDATE`
+		existing, err := storage.ExecMany[featuredNews](ctx, conn, sql, fallbackLanguage, FeaturedNewsType, newsIDs)
+		if err != nil {
+			return errors.Wrapf(err, "failed to select featured news %#v", newsIDs)
+		}
+		if len(existing) != len(newsIDs) {
+			return errors.Wrapf(ErrNotFound, "at least one of %#v is not a published, untargeted featured news article", newsIDs)
+		}
+		sql = `UPDATE news
+			   SET priority = p.priority,
+				   updated_at = $4
+			   FROM (SELECT n.language,
+							n.id,
+							n.priority AS previous_priority,
+							n.updated_at AS previous_updated_at,
+							COALESCE((SELECT $3 - o.ix
+									  FROM unnest($2::TEXT[]) WITH ORDINALITY o(id, ix)
+									  WHERE o.id = n.id), 0) AS priority
+					 FROM news n
+						  JOIN news n_en
+							ON n_en.language = $5
+						   AND n_en.id = n.id
+						   AND n_en.draft = FALSE
+						   AND n_en.targeting IS NULL
+					 WHERE n.type = $1) p
+			   WHERE news.language = p.language
+				 AND news.id = p.id
+				 AND news.priority != p.priority
+			   RETURNING news.*, p.previous_priority, p.previous_updated_at`
+		reordered, err = storage.ExecMany[reorderedNews](ctx, conn, sql, FeaturedNewsType, newsIDs, int64(len(newsIDs)+1), now.Time, fallbackLanguage)
+
+		return errors.Wrapf(err, "failed to reorder featured news %#v", newsIDs)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "transaction failed for %#v", newsIDs)
+	}
+	snapshots := make([]*TaggedNewsSnapshot, 0, len(reordered))
+	for _, nws := range reordered {
+		nws.ImageURL = r.pictureClient.DownloadURL(nws.ImageURL)
+		before := *nws.News
+		before.Priority, before.UpdatedAt = &nws.PreviousPriority, nws.PreviousUpdatedAt
+		snapshots = append(snapshots, &TaggedNewsSnapshot{TaggedNews: &TaggedNews{News: nws.News}, Before: &TaggedNews{News: &before}})
+	}
+
+	return errors.Wrapf(sendMessagesConcurrently(ctx, r.sendTaggedNewsSnapshotMessage, snapshots), "failed to sendTaggedNewsSnapshotMessages:%#v", snapshots)
+}
+
+// featuredNewsOrder is the order featured news are shown in: pinned ones first, then by priority,
+// then either rotated, for the same priority, or by recency. It's always based on the `n_en` article.
+func featuredNewsOrder(nowArgPosition, rotationArgPosition int) string {
+	return fmt.Sprintf(`COALESCE(n_en.pinned_until > $%[1]v, FALSE) DESC,
+				n_en.priority DESC,
+				(CASE WHEN $%[2]v = '' THEN '' ELSE md5(n_en.id || $%[2]v) END),
+				n_en.created_at DESC`, nowArgPosition, rotationArgPosition)
+}
+
+func (cfg *config) featuredRotation(now *time.Time) string {
+	if cfg.FeaturedRotationPeriod <= 0 {
+		return ""
+	}
+
+	return fmt.Sprint(now.UnixNano() / int64(cfg.FeaturedRotationPeriod))
+}
+
+func (cfg *config) featuredSlots() uint64 {
+	if cfg.FeaturedSlots == 0 {
+		return 1
+	}
+
+	return cfg.FeaturedSlots
+}
+
+func priority(p *int64) int64 {
+	if p == nil {
+		return 0
+	}
+
+	return *p
+}
+
+func pinnedUntil(t *time.Time) *stdlibtime.Time {
+	if t == nil {
+		return nil
+	}
+
+	return t.Time
+}
@@ -17,7 +17,30 @@ func (r *repository) GetNews(ctx context.Context, newsType Type, language string
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "get news failed because context failed")
 	}
-	args := []any{requestingUserID(ctx), language, newsType, int64(limit), int64(offset), createdAfter.Time}
+	if r.cache != nil {
+		return r.getCachedNews(ctx, newsType, language, limit, offset, createdAfter)
+	}
+	country, appVersion, segments := audience(ctx)
+	orderBy := `(CASE WHEN n.type = 'regular' OR n_en.type = 'regular'
+					THEN ((nvu_en.created_at IS NULL AND nvu.created_at IS NULL) OR COALESCE(n_en.created_at,n_en.created_at) >= $6::timestamp)
+					ELSE FALSE
+				END) DESC,
+				COALESCE(n_en.created_at,n.created_at) DESC`
+	var extraArgs []any
+	if newsType == FeaturedNewsType {
+		if offset >= r.cfg.featuredSlots() {
+			return []*PersonalNews{}, nil
+		}
+		if limit > r.cfg.featuredSlots()-offset {
+			limit = r.cfg.featuredSlots() - offset
+		}
+		now := time.Now()
+		orderBy = featuredNewsOrder(10, 11) //nolint:gomnd // Arg positions.
+		extraArgs = append(extraArgs, now.Time, r.cfg.featuredRotation(now))
+	}
+	args := append([]any{
+		requestingUserID(ctx), r.cfg.fallbackChain(language), newsType, int64(limit), int64(offset), createdAfter.Time, country, appVersion, segments,
+	}, extraArgs...)
 	sql := fmt.Sprintf(`SELECT (nvu.created_at IS NOT NULL OR nvu_en.created_at IS NOT NULL OR COALESCE(n.created_at,n_en.created_at) < $6::timestamp) AS viewed,
 					    COALESCE(n_en.created_at,n.created_at) AS created_at,
 						COALESCE(n.updated_at, n_en.updated_at) AS updated_at,
@@ -28,9 +51,16 @@ func (r *repository) GetNews(ctx context.Context, newsType Type, language string
 						COALESCE(n.language, n_en.language) AS language,
 						COALESCE(n.title, n_en.title) AS title,
 						COALESCE(n.image_url, n_en.image_url) AS image_url,
-						COALESCE(n.url, n_en.url) AS url
+						COALESCE(n.url, n_en.url) AS url,
+						(CASE WHEN n.id IS NOT NULL THEN n.variants ELSE n_en.variants END) AS variants
 			FROM news n_en
-				LEFT JOIN news n on n.id = n_en.id and n.language = $2
+				LEFT JOIN LATERAL (SELECT *
+								   FROM news
+								   WHERE id = n_en.id
+									 AND language = ANY($2)
+									 AND draft = FALSE
+								   ORDER BY array_position($2, language)
+								   LIMIT 1) n ON TRUE
 				LEFT JOIN news_viewed_by_users nvu 
 					   ON nvu.language = n.language
 					  AND nvu.news_id = n.id
@@ -43,13 +73,10 @@ func (r *repository) GetNews(ctx context.Context, newsType Type, language string
 				LEFT JOIN news_views v_en ON v_en.id = n_en.id
 			WHERE n_en.language = '%[1]v'
 				  AND n_en.type = $3
-			ORDER BY 
-				(CASE WHEN n.type = 'regular' OR n_en.type = 'regular'
-					THEN ((nvu_en.created_at IS NULL AND nvu.created_at IS NULL) OR COALESCE(n_en.created_at,n_en.created_at) >= $6::timestamp)
-					ELSE FALSE
-				END) DESC,
-				COALESCE(n_en.created_at,n.created_at) DESC
-			LIMIT $4 OFFSET $5`, fallbackLanguage)
+				  AND n_en.draft = FALSE
+				  AND news_targeting_matches(COALESCE(n.targeting, n_en.targeting), $7, $8, $9)
+			ORDER BY %[2]v
+			LIMIT $4 OFFSET $5`, fallbackLanguage, orderBy)
 	result, err := storage.Select[PersonalNews](ctx, r.db, sql, args...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get news for args:%#v", args...)
@@ -65,6 +92,10 @@ func (r *repository) GetNews(ctx context.Context, newsType Type, language string
 		elem.NotificationChannels = nil
 		elem.UpdatedAt = nil
 		elem.ImageURL = r.pictureClient.DownloadURL(elem.ImageURL)
+		elem.applyVariant(requestingUserID(ctx))
+	}
+	if err = r.enhanceWithReactions(ctx, result); err != nil {
+		return nil, errors.Wrapf(err, "failed to enhanceWithReactions for args:%#v", args...)
 	}
 
 	return result, nil
@@ -75,15 +106,27 @@ func (r *repository) GetUnreadNewsCount(ctx context.Context, language string, cr
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "context failed")
 	}
-	args := []any{requestingUserID(ctx), language, RegularNewsType, FeaturedNewsType, createdAfter.Time}
+	if r.cache != nil {
+		return r.getCachedUnreadNewsCount(ctx, language, createdAfter)
+	}
+	country, appVersion, segments := audience(ctx)
+	now := time.Now()
+	args := []any{
+		requestingUserID(ctx), r.cfg.fallbackChain(language), RegularNewsType, FeaturedNewsType, createdAfter.Time, country, appVersion, segments,
+		now.Time, r.cfg.featuredRotation(now), int64(r.cfg.featuredSlots()),
+	}
 	sql := fmt.Sprintf(`
-		WITH featured_count AS (
-			 SELECT (CASE WHEN (nvu.created_at IS NULL AND nvu_en.created_at IS NULL) THEN 1 ELSE 0 END) AS count
+		WITH featured AS (
+			 SELECT (nvu.created_at IS NULL AND nvu_en.created_at IS NULL AND (n.created_at >= $5 OR n_en.created_at >= $5)) AS unread
 				FROM news n_en
-				  LEFT JOIN news n 
-				 ON n.id = n_en.id 
-				AND n.language = $2
-				AND n.type = $4
+				  LEFT JOIN LATERAL (SELECT *
+									 FROM news
+									 WHERE id = n_en.id
+									   AND language = ANY($2)
+									   AND type = $4
+									   AND draft = FALSE
+									 ORDER BY array_position($2, language)
+									 LIMIT 1) n ON TRUE
 				  LEFT JOIN news_viewed_by_users nvu
 							 ON nvu.language = n.language
 								AND nvu.news_id = n.id
@@ -95,17 +138,25 @@ func (r *repository) GetUnreadNewsCount(ctx context.Context, language string, cr
 				WHERE
 				  n_en.language = '%[1]v'
 				  AND n_en.type = $4
-				  AND (n.created_at >= $5 OR n_en.created_at >= $5)
-				ORDER BY COALESCE(n.created_at, n_en.created_at) DESC LIMIT 1
+				  AND n_en.draft = FALSE
+				  AND news_targeting_matches(COALESCE(n.targeting, n_en.targeting), $6, $7, $8)
+				ORDER BY %[2]v
+				LIMIT $11
+		), featured_count AS (
+			SELECT COUNT(1) FILTER (WHERE unread) AS count FROM featured
 		) 
 		SELECT featured_count.count + regular_count.count as count FROM 
 		(
 			SELECT COUNT(COALESCE(n.id,n_en.id)) as count
 				FROM news n_en	
-				    	LEFT JOIN news n 
-						 ON n.id = n_en.id 
-						AND n.language = $2
-				    	AND n.type = $3
+						LEFT JOIN LATERAL (SELECT *
+										   FROM news
+										   WHERE id = n_en.id
+											 AND language = ANY($2)
+											 AND type = $3
+											 AND draft = FALSE
+										   ORDER BY array_position($2, language)
+										   LIMIT 1) n ON TRUE
 						LEFT JOIN news_viewed_by_users nvu
 							ON nvu.language = n.language
 							AND nvu.news_id = n.id 
@@ -117,9 +168,11 @@ func (r *repository) GetUnreadNewsCount(ctx context.Context, language string, cr
 				WHERE n_en.language = '%[1]v'
 					AND n_en.type = $3
 					AND (n_en.created_at >= $5 OR n.created_at >= $5)
+					AND n_en.draft = FALSE
+					AND news_targeting_matches(COALESCE(n.targeting, n_en.targeting), $6, $7, $8)
 					AND nvu_en.created_at IS NULL 
 				    AND nvu.created_at IS NULL
-			) regular_count CROSS JOIN featured_count`, fallbackLanguage)
+			) regular_count CROSS JOIN featured_count`, fallbackLanguage, featuredNewsOrder(9, 10)) //nolint:gomnd // Arg positions.
 	result, err := storage.Get[UnreadNewsCount](ctx, r.db, sql, args...)
 	if err != nil {
 		if storage.IsErr(err, storage.ErrNotFound) { // All news are filtered by createdAfter.
@@ -132,6 +185,38 @@ func (r *repository) GetUnreadNewsCount(ctx context.Context, language string, cr
 	return &UnreadNewsCount{Count: result.Count}, nil
 }
 
+// GetNewsFeed returns the latest public (not targeted) regular and featured news of that language, to be syndicated.
+func (r *repository) GetNewsFeed(ctx context.Context, language string, limit uint64) ([]*TaggedNews, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT array_agg(t.news_tag ORDER BY t.created_at) filter (where t.news_tag is not null) AS tags,
+				   n.*
+			FROM news n
+				  LEFT JOIN news_tags_per_news t
+						 ON t.language = n.language
+						AND t.news_id  = n.id
+			WHERE n.language = $1
+			  AND n.type = ANY($2)
+			  AND n.targeting IS NULL
+			  AND n.draft = FALSE
+			GROUP BY n.language, n.id
+			ORDER BY n.created_at DESC
+			LIMIT $3`
+	result, err := storage.Select[TaggedNews](ctx, r.db, sql, language, []Type{RegularNewsType, FeaturedNewsType}, int64(limit))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select news feed for language:%v", language)
+	}
+	for _, elem := range result {
+		elem.NotificationChannels = nil
+		elem.Variants = nil
+		elem.Draft = nil
+		elem.ImageURL = r.pictureClient.DownloadURL(elem.ImageURL)
+	}
+
+	return result, nil
+}
+
 func (r *repository) getNewsByPK(ctx context.Context, newsID, language string) (*TaggedNews, error) {
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "context failed")
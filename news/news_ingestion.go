@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/husky/notifications"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+type (
+	// | ingestedFeed is either a RSS 2.0 or an Atom document.
+	ingestedFeed struct {
+		Channel struct {
+			Items []*ingestedRSSItem `xml:"item"`
+		} `xml:"channel"`
+		Entries []*ingestedAtomEntry `xml:"entry"`
+	}
+	ingestedRSSItem struct {
+		Enclosure *struct {
+			URL  string `xml:"url,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"enclosure"`
+		MediaContent *struct {
+			URL string `xml:"url,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ content"`
+		MediaThumbnail *struct {
+			URL string `xml:"url,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+		Title      string   `xml:"title"`
+		Link       string   `xml:"link"`
+		Categories []string `xml:"category"`
+	}
+	ingestedAtomEntry struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	}
+	ingestedEntry struct {
+		Title, URL, ImageURL string
+		Tags                 []Tag
+	}
+)
+
+const (
+	maxIngestedImageSize = 10 << 20
+	maxFetchedSize       = 20 << 20
+	fetchTimeout         = 30 * stdlibtime.Second
+)
+
+//nolint:gochecknoglobals // It's reused for all the feeds and their images, so that the connections are pooled.
+var feedClient = &http.Client{Timeout: fetchTimeout}
+
+func (p *processor) startFeedIngester(ctx context.Context) {
+	if len(p.cfg.FeedSources) == 0 {
+		return
+	}
+	interval := p.cfg.FeedPollingInterval
+	if interval == 0 {
+		interval = 15 * stdlibtime.Minute //nolint:gomnd // A sane default.
+	}
+	ticker := stdlibtime.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, source := range p.cfg.FeedSources {
+			reqCtx, cancel := context.WithTimeout(ctx, interval)
+			log.Error(errors.Wrapf(p.ingestFeed(reqCtx, source), "failed to ingestFeed for %#v", source))
+			cancel()
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *processor) ingestFeed(ctx context.Context, source *feedSource) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	content, _, err := fetch(ctx, source.URL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch feed %v", source.URL)
+	}
+	entries, err := parseFeed(content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse feed %v", source.URL)
+	}
+	errs := make([]error, 0, len(entries))
+	for _, entry := range entries {
+		errs = append(errs, errors.Wrapf(p.ingestEntry(ctx, source, entry), "failed to ingestEntry %#v from %v", entry, source.URL))
+	}
+
+	return errors.Wrap(multierror.Append(nil, errs...).ErrorOrNil(), "failed to ingest at least one entry")
+}
+
+func (p *processor) ingestEntry(ctx context.Context, source *feedSource, entry *ingestedEntry) error {
+	if entry.URL == "" || entry.Title == "" {
+		return nil
+	}
+	if !isHTTPURL(entry.URL) {
+		log.Warn(fmt.Sprintf("skipping ingested news `%v` from %v, because its url is not http(s)", entry.URL, source.URL))
+
+		return nil
+	}
+	if exists, err := p.newsWithURLExists(ctx, CanonicalURL(entry.URL), source.Language); err != nil || exists {
+		return errors.Wrapf(err, "failed to check if news with url %v exists", entry.URL)
+	}
+	if entry.ImageURL == "" {
+		log.Warn(fmt.Sprintf("skipping ingested news `%v` from %v, because it has no image", entry.URL, source.URL))
+
+		return nil
+	}
+	if !isHTTPURL(entry.ImageURL) {
+		log.Warn(fmt.Sprintf("skipping ingested news `%v` from %v, because its image `%v` is not http(s)", entry.URL, source.URL, entry.ImageURL))
+
+		return nil
+	}
+	image, err := downloadImage(ctx, entry.ImageURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download image %v", entry.ImageURL)
+	}
+	nws := source.taggedNews(entry)
+	if err = p.CreateNews(ctx, []*TaggedNews{nws}, image); err != nil && !errors.Is(err, ErrDuplicate) {
+		return errors.Wrapf(err, "failed to CreateNews for %#v", nws)
+	}
+
+	return nil
+}
+
+func (p *processor) newsWithURLExists(ctx context.Context, newsURL, language string) (bool, error) {
+	type exists struct {
+		Exists bool
+	}
+	sql := `SELECT EXISTS(SELECT 1 FROM news WHERE canonical_url = $1 AND language = $2) AS exists`
+	resp, err := storage.Get[exists](ctx, p.db, sql, newsURL, language)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to select news by (url:%v,language:%v)", newsURL, language)
+	}
+
+	return resp.Exists, nil
+}
+
+func (src *feedSource) taggedNews(entry *ingestedEntry) *TaggedNews {
+	tags := make(Tags, 0, len(src.Tags)+len(entry.Tags))
+	seenTags := make(map[Tag]struct{}, cap(tags))
+	for _, tag := range append(append(make([]Tag, 0, cap(tags)), src.Tags...), entry.Tags...) {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag == "" {
+			continue
+		}
+		if _, seen := seenTags[tag]; !seen {
+			seenTags[tag] = struct{}{}
+			tags = append(tags, tag)
+		}
+	}
+	newsType := src.Type
+	if newsType == "" {
+		newsType = RegularNewsType
+	}
+	var channels *notifications.NotificationChannels
+	if len(src.NotificationChannels) != 0 {
+		enum := users.Enum[notifications.NotificationChannel](src.NotificationChannels)
+		channels = &notifications.NotificationChannels{NotificationChannels: &enum}
+	}
+	draft := !src.Publish
+
+	return &TaggedNews{
+		Tags: &tags,
+		News: &News{
+			NotificationChannels: channels,
+			Draft:                &draft,
+			Type:                 newsType,
+			Language:             strings.ToLower(src.Language),
+			Title:                entry.Title,
+			URL:                  entry.URL,
+		},
+	}
+}
+
+func parseFeed(content []byte) ([]*ingestedEntry, error) {
+	feed := new(ingestedFeed)
+	if err := xml.Unmarshal(content, feed); err != nil {
+		return nil, errors.Wrapf(err, "failed to xml unmarshal %v", string(content))
+	}
+	entries := make([]*ingestedEntry, 0, len(feed.Channel.Items)+len(feed.Entries))
+	for _, item := range feed.Channel.Items {
+		entry := &ingestedEntry{Title: strings.TrimSpace(item.Title), URL: strings.TrimSpace(item.Link), Tags: item.Categories}
+		switch {
+		case item.Enclosure != nil && strings.HasPrefix(item.Enclosure.Type, "image/"):
+			entry.ImageURL = item.Enclosure.URL
+		case item.MediaContent != nil:
+			entry.ImageURL = item.MediaContent.URL
+		case item.MediaThumbnail != nil:
+			entry.ImageURL = item.MediaThumbnail.URL
+		}
+		entries = append(entries, entry)
+	}
+	for _, atomEntry := range feed.Entries {
+		entry := &ingestedEntry{Title: strings.TrimSpace(atomEntry.Title)}
+		for _, link := range atomEntry.Links {
+			switch {
+			case (link.Rel == "" || link.Rel == "alternate") && entry.URL == "":
+				entry.URL = strings.TrimSpace(link.Href)
+			case link.Rel == "enclosure" && strings.HasPrefix(link.Type, "image/") && entry.ImageURL == "":
+				entry.ImageURL = link.Href
+			}
+		}
+		for _, category := range atomEntry.Categories {
+			entry.Tags = append(entry.Tags, category.Term)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func downloadImage(ctx context.Context, location string) (*multipart.FileHeader, error) {
+	content, contentType, err := fetch(ctx, location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch image %v", location)
+	}
+	if len(content) > maxIngestedImageSize {
+		return nil, errors.Errorf("image %v is too big: %v bytes", location, len(content))
+	}
+	var extension string
+	switch mediaType, _, _ := mime.ParseMediaType(contentType); mediaType { //nolint:errcheck // Not needed, we fallback to the file extension.
+	case "image/png":
+		extension = ".png"
+	case "image/jpeg":
+		extension = ".jpg"
+	default:
+		if lastDotIdx := strings.LastIndex(location, "."); lastDotIdx > 0 {
+			extension = strings.ToLower(location[lastDotIdx:])
+		}
+		if extension == ".jpeg" {
+			extension = ".jpg"
+		}
+	}
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="image"; filename="image%v"`, extension))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create multipart image part")
+	}
+	if _, err = part.Write(content); err != nil {
+		return nil, errors.Wrap(err, "failed to write multipart image part")
+	}
+	if err = writer.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close multipart writer")
+	}
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(maxIngestedImageSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read multipart image form")
+	}
+
+	return form.File["image"][0], nil
+}
+
+// fetch only supports http(s), because the locations come from the feeds, so anything else, like the local files, must not be reachable.
+func fetch(ctx context.Context, location string) (content []byte, contentType string, err error) {
+	if !isHTTPURL(location) {
+		return nil, "", errors.Errorf("unsupported location %v, only http(s) is allowed", location)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, http.NoBody)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to build request for %v", location)
+	}
+	resp, err := feedClient.Do(req)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to GET %v", location)
+	}
+	defer func() {
+		if cErr := resp.Body.Close(); cErr != nil && err == nil {
+			err = errors.Wrapf(cErr, "failed to close response body of %v", location)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.Errorf("unexpected status code %v for %v", resp.StatusCode, location)
+	}
+	if content, err = io.ReadAll(io.LimitReader(resp.Body, maxFetchedSize+1)); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to read response body of %v", location)
+	}
+	if len(content) > maxFetchedSize {
+		return nil, "", errors.Errorf("response body of %v is bigger than %v bytes", location, maxFetchedSize)
+	}
+
+	return content, resp.Header.Get("Content-Type"), nil
+}
+
+func isHTTPURL(location string) bool {
+	parsed, err := url.Parse(location)
+
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newLocalFeedServer(tb testing.TB) *httptest.Server {
+	tb.Helper()
+	files := http.FileServer(http.Dir(".testdata"))
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/feed.xml" {
+			files.ServeHTTP(writer, req)
+
+			return
+		}
+		content, err := os.ReadFile(filepath.Join(".testdata", "feed.xml"))
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+		writer.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = writer.Write(bytes.ReplaceAll(content, []byte("{{host}}"), []byte(srv.URL))) //nolint:errcheck // It's just a test.
+	}))
+	tb.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestFetchAndParseLocalFeed(t *testing.T) {
+	t.Parallel()
+	srv := newLocalFeedServer(t)
+	ctx := context.Background()
+
+	content, _, err := fetch(ctx, srv.URL+"/feed.xml")
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	entries, err := parseFeed(content)
+	if err != nil {
+		t.Fatalf("parseFeed failed: %v", err)
+	}
+	expected := []*ingestedEntry{
+		{
+			Title:    "The importance of the blockchain technology",
+			URL:      "https://ice.io/blog/the-importance-of-the-blockchain-technology",
+			ImageURL: srv.URL + "/profilePic1.jpg",
+			Tags:     []Tag{"Blockchain", "Technology"},
+		},
+		{
+			Title:    "How mining works",
+			URL:      "https://ice.io/blog/how-mining-works",
+			ImageURL: srv.URL + "/profilePic2.png",
+			Tags:     []Tag{"Mining"},
+		},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("parseFeed returned %v entries, expected %v", len(entries), len(expected))
+	}
+	for ix, entry := range entries {
+		if entry.Title != expected[ix].Title || entry.URL != expected[ix].URL || entry.ImageURL != expected[ix].ImageURL {
+			t.Errorf("entry %v = %#v, expected %#v", ix, entry, expected[ix])
+		}
+		if len(entry.Tags) != len(expected[ix].Tags) {
+			t.Fatalf("entry %v tags = %v, expected %v", ix, entry.Tags, expected[ix].Tags)
+		}
+		for tagIx, tag := range entry.Tags {
+			if tag != expected[ix].Tags[tagIx] {
+				t.Errorf("entry %v tags = %v, expected %v", ix, entry.Tags, expected[ix].Tags)
+			}
+		}
+	}
+	for ix, extension := range []string{".jpg", ".png"} {
+		image, iErr := downloadImage(ctx, entries[ix].ImageURL)
+		if iErr != nil {
+			t.Fatalf("downloadImage(%v) failed: %v", entries[ix].ImageURL, iErr)
+		}
+		if image.Filename != "image"+extension || image.Size == 0 {
+			t.Errorf("downloadImage(%v) = (%v, %v bytes), expected a non empty image%v", entries[ix].ImageURL, image.Filename, image.Size, extension)
+		}
+	}
+}
+
+func TestFetchOnlyHTTP(t *testing.T) {
+	t.Parallel()
+	for _, location := range []string{".testdata/feed.xml", "file://.testdata/feed.xml", "ftp://ice.io/feed.xml", "http:///feed.xml"} {
+		if _, _, err := fetch(context.Background(), location); err == nil {
+			t.Errorf("fetch(%v) succeeded, expected an error", location)
+		}
+	}
+}
+
+func TestFetchUnexpectedStatus(t *testing.T) {
+	t.Parallel()
+	srv := newLocalFeedServer(t)
+	if _, _, err := fetch(context.Background(), srv.URL+"/missing.xml"); err == nil {
+		t.Error("fetch succeeded for a missing feed, expected an error")
+	}
+}
@@ -30,6 +30,9 @@ func (r *repository) ModifyNews(ctx context.Context, news *TaggedNews, image *mu
 	if lu := lastUpdatedAt(ctx); lu != nil && oldNews.UpdatedAt.UnixNano() != lu.UnixNano() {
 		return ErrRaceCondition
 	}
+	if err = r.detectDuplicates(ctx, news); err != nil {
+		return errors.Wrapf(err, "failed to detectDuplicates for news:%#v", news)
+	}
 	news.UpdatedAt = time.Now()
 	if err = r.validateAndUploadImage(ctx, image, news.ID, news.UpdatedAt); err != nil {
 		return errors.Wrapf(err, "failed to validateAndUploadImage for news:%#v", news)
@@ -86,8 +89,37 @@ func (r *repository) updateNews(ctx context.Context, news *TaggedNews) error { /
 		fieldIndex++
 	}
 	if news.URL != "" {
-		args = append(args, news.URL)
-		sql += fmt.Sprintf(", URL = $%v", fieldIndex)
+		args = append(args, news.URL, CanonicalURL(news.URL))
+		sql += fmt.Sprintf(", URL = $%v, CANONICAL_URL = $%v", fieldIndex, fieldIndex+1)
+		fieldIndex += 2
+	}
+	if news.Targeting != nil {
+		args = append(args, targeting(news.Targeting))
+		sql += fmt.Sprintf(", TARGETING = $%v", fieldIndex)
+		fieldIndex++
+	}
+	if news.Variants != nil {
+		// Changing the variants restarts the experiment.
+		args = append(args, variants(news.Variants))
+		sql += fmt.Sprintf(`, VARIANTS = $%[1]v,
+			EXPERIMENT_STARTED_AT = (CASE WHEN $%[1]v::JSONB IS NULL THEN NULL
+										  WHEN VARIANTS IS NOT DISTINCT FROM $%[1]v::JSONB THEN EXPERIMENT_STARTED_AT
+										  ELSE $1 END)`, fieldIndex)
+		fieldIndex++
+	}
+	if news.Draft != nil {
+		args = append(args, *news.Draft)
+		sql += fmt.Sprintf(", DRAFT = $%v", fieldIndex)
+		fieldIndex++
+	}
+	if news.Priority != nil {
+		args = append(args, *news.Priority)
+		sql += fmt.Sprintf(", PRIORITY = $%v", fieldIndex)
+		fieldIndex++
+	}
+	if news.PinnedUntil != nil {
+		args = append(args, pinnedUntil(news.PinnedUntil))
+		sql += fmt.Sprintf(", PINNED_UNTIL = $%v", fieldIndex)
 		fieldIndex++
 	}
 	args = append(args, news.ID, news.Language)
@@ -118,9 +150,28 @@ func (n *TaggedNews) override(news *TaggedNews) *TaggedNews {
 	nws.Title = mergeStringField(n.Title, news.Title)
 	nws.ImageURL = mergeStringField(n.ImageURL, news.ImageURL)
 	nws.URL = mergeStringField(n.URL, news.URL)
+	if news.URL != "" {
+		canonicalURL := CanonicalURL(news.URL)
+		nws.CanonicalURL = &canonicalURL
+	}
 	if news.Tags != nil && len(*news.Tags) != 0 {
 		nws.Tags = news.Tags
 	}
+	if news.Targeting != nil {
+		nws.Targeting = targeting(news.Targeting)
+	}
+	if news.Variants != nil {
+		nws.Variants = variants(news.Variants)
+	}
+	if news.Draft != nil {
+		nws.Draft = news.Draft
+	}
+	if news.Priority != nil {
+		nws.Priority = news.Priority
+	}
+	if news.PinnedUntil != nil {
+		nws.PinnedUntil = news.PinnedUntil
+	}
 	if news.Views > 0 {
 		nws.Views = news.Views
 	}
@@ -137,9 +188,15 @@ func (r *repository) IncrementViews(ctx context.Context, newsID, language string
 		NewsID:    newsID,
 		Language:  language,
 		UserID:    requestingUserID(ctx),
+		Source:    viewSource(ctx),
+	}
+	variant, err := r.getVariant(ctx, newsID, language, tuple.UserID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to getVariant for %#v", tuple)
 	}
-	args := []any{tuple.CreatedAt.Time, tuple.NewsID, tuple.Language, tuple.UserID}
-	sql := `INSERT INTO NEWS_VIEWED_BY_USERS (created_at, news_id, language, user_id) VALUES($1, $2, $3, $4)`
+	tuple.Variant = variant
+	args := []any{tuple.CreatedAt.Time, tuple.NewsID, tuple.Language, tuple.UserID, tuple.Variant, tuple.Source}
+	sql := `INSERT INTO NEWS_VIEWED_BY_USERS (created_at, news_id, language, user_id, variant, source) VALUES($1, $2, $3, $4, $5, $6)`
 	if _, err := storage.Exec(ctx, r.db, sql, args...); err != nil {
 		return errors.Wrapf(err, "failed to insert NEWS_VIEWED_BY_USERS %#v", tuple)
 	}
@@ -189,7 +246,7 @@ func (r *repository) sendNewsViewedMessage(ctx context.Context, vn *ViewedNews)
 func detectAndParseDuplicateDatabaseError(err error) error {
 	if storage.IsErr(err, storage.ErrDuplicate) {
 		field := ""
-		if storage.IsErr(err, storage.ErrDuplicate, "urllanguageix") { //nolint:gocritic // Switch case not possible.
+		if storage.IsErr(err, storage.ErrDuplicate, "urllanguageix") || storage.IsErr(err, storage.ErrDuplicate, "canonicalurllanguageix") { //nolint:gocritic,lll // .
 			field = "url"
 		} else if storage.IsErr(err, storage.ErrDuplicate, "pk") {
 			field = "id"
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) AddReaction(ctx context.Context, newsID, language string, reaction Reaction) error { //nolint:funlen // A lot of negative flow handling.
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if !r.cfg.isReactionAllowed(reaction) {
+		return errors.Wrapf(ErrInvalidReaction, "reaction `%v` is not allowed", reaction)
+	}
+	tuple := &NewsReaction{
+		CreatedAt: time.Now(),
+		NewsID:    newsID,
+		Language:  language,
+		UserID:    requestingUserID(ctx),
+		Reaction:  reaction,
+	}
+	variant, err := r.getVariant(ctx, newsID, language, tuple.UserID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to getVariant for %#v", tuple)
+	}
+	tuple.Variant = variant
+	previous, err := r.upsertReaction(ctx, tuple)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrRelationNotFound) {
+			err = ErrNotFound
+		}
+
+		return errors.Wrapf(err, "failed to upsert news reaction %#v", tuple)
+	}
+	if previous == reaction {
+		return nil
+	}
+	tuple.PreviousReaction = previous
+	if err = r.sendNewsReactionMessage(ctx, tuple); err != nil {
+		bErr := errors.Wrapf(err, "failed to sendNewsReactionMessage for %#v", tuple)
+		var rErr error
+		if previous == "" {
+			rErr = r.deleteReaction(ctx, tuple)
+		} else {
+			_, rErr = r.upsertReaction(ctx, &NewsReaction{
+				CreatedAt: tuple.CreatedAt,
+				NewsID:    newsID,
+				Language:  language,
+				UserID:    tuple.UserID,
+				Reaction:  previous,
+				Variant:   variant,
+			})
+		}
+		if rErr != nil {
+			return multierror.Append(bErr, errors.Wrapf(rErr, "[rollback]failed to revert news reaction %#v", tuple)).ErrorOrNil() //nolint:wrapcheck // .
+		}
+
+		return bErr
+	}
+
+	return nil
+}
+
+func (r *repository) RemoveReaction(ctx context.Context, newsID, language string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	tuple := &NewsReaction{
+		CreatedAt: time.Now(),
+		NewsID:    newsID,
+		Language:  language,
+		UserID:    requestingUserID(ctx),
+	}
+	sql := `DELETE FROM news_reactions_by_users WHERE language = $1 AND news_id = $2 AND user_id = $3 RETURNING *`
+	removed, err := storage.ExecOne[NewsReaction](ctx, r.db, sql, language, newsID, tuple.UserID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete news reaction for %#v", tuple)
+	}
+	tuple.PreviousReaction = removed.Reaction
+	tuple.Variant = removed.Variant
+	if err = r.sendNewsReactionMessage(ctx, tuple); err != nil {
+		bErr := errors.Wrapf(err, "failed to sendNewsReactionMessage for %#v", tuple)
+		if _, rErr := r.upsertReaction(ctx, removed); rErr != nil {
+			return multierror.Append(bErr, errors.Wrapf(rErr, "[rollback]failed to re-insert news reaction %#v", removed)).ErrorOrNil() //nolint:wrapcheck // .
+		}
+
+		return bErr
+	}
+
+	return nil
+}
+
+func (r *repository) upsertReaction(ctx context.Context, tuple *NewsReaction) (previous Reaction, err error) {
+	if ctx.Err() != nil {
+		return "", errors.Wrap(ctx.Err(), "context failed")
+	}
+	type previousReaction struct {
+		Reaction Reaction
+	}
+	sql := `WITH previous AS (
+				SELECT reaction
+				FROM news_reactions_by_users
+				WHERE language = $2
+				  AND news_id = $3
+				  AND user_id = $4
+			), upserted AS (
+				INSERT INTO news_reactions_by_users (created_at, language, news_id, user_id, reaction, variant) VALUES ($1, $2, $3, $4, $5, $6)
+					ON CONFLICT (language, news_id, user_id)
+					DO UPDATE
+						SET reaction = EXCLUDED.reaction,
+							created_at = EXCLUDED.created_at,
+							variant = EXCLUDED.variant
+					WHERE news_reactions_by_users.reaction != EXCLUDED.reaction
+			)
+			SELECT COALESCE((SELECT reaction FROM previous), '') AS reaction`
+	resp, err := storage.ExecOne[previousReaction](ctx, r.db, sql, tuple.CreatedAt.Time, tuple.Language, tuple.NewsID, tuple.UserID, tuple.Reaction, tuple.Variant) //nolint:lll // .
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to upsert news_reactions_by_users %#v", tuple)
+	}
+
+	return resp.Reaction, nil
+}
+
+func (r *repository) deleteReaction(ctx context.Context, tuple *NewsReaction) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `DELETE FROM news_reactions_by_users WHERE language = $1 AND news_id = $2 AND user_id = $3`
+	_, err := storage.Exec(ctx, r.db, sql, tuple.Language, tuple.NewsID, tuple.UserID)
+
+	return errors.Wrapf(err, "failed to delete news_reactions_by_users %#v", tuple)
+}
+
+func (r *repository) enhanceWithReactions(ctx context.Context, news []*PersonalNews) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if len(news) == 0 {
+		return nil
+	}
+	ids, languages := make([]string, 0, len(news)), make([]string, 0, len(news))
+	for _, nws := range news {
+		ids, languages = append(ids, nws.ID), append(languages, nws.Language)
+	}
+	type reactionCount struct {
+		NewsID   string
+		Reaction Reaction
+		Count    uint64
+		Reacted  bool
+	}
+	sql := `SELECT news_id,
+				   reaction,
+				   COUNT(1) AS count,
+				   bool_or(user_id = $1) AS reacted
+			FROM news_reactions_by_users
+			WHERE (language, news_id) IN (SELECT * FROM unnest($3::TEXT[], $2::TEXT[]))
+			GROUP BY news_id, reaction`
+	counts, err := storage.Select[reactionCount](ctx, r.db, sql, requestingUserID(ctx), ids, languages)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select news reaction counts for ids:%#v, languages:%#v", ids, languages)
+	}
+	countsByNewsID := make(map[string][]*reactionCount, len(news))
+	for _, count := range counts {
+		countsByNewsID[count.NewsID] = append(countsByNewsID[count.NewsID], count)
+	}
+	for _, nws := range news {
+		for _, count := range countsByNewsID[nws.ID] {
+			if nws.Reactions == nil {
+				nws.Reactions = make(map[Reaction]uint64, len(countsByNewsID[nws.ID]))
+			}
+			nws.Reactions[count.Reaction] = count.Count
+			if count.Reacted {
+				nws.Reaction = count.Reaction
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) sendNewsReactionMessage(ctx context.Context, nr *NewsReaction) error {
+	valueBytes, err := json.MarshalContext(ctx, nr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", nr)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "husky"},
+		Key:     nr.NewsID + "~~~" + nr.Language + "~~~" + nr.UserID,
+		Topic:   r.cfg.MessageBroker.Topics[3].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send news reaction message to broker")
+}
+
+func (cfg *config) isReactionAllowed(reaction Reaction) bool {
+	if reaction == LikeReaction {
+		return true
+	}
+	for _, allowed := range cfg.Reactions {
+		if allowed == reaction {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package news
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func (r *repository) GetTranslationCoverage(ctx context.Context, languages []string, limit, offset uint64) ([]*TranslationCoverage, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	expectedLanguages, err := r.expectedTranslationLanguages(ctx, languages)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get expected translation languages for %#v", languages)
+	}
+	sql := fmt.Sprintf(`SELECT n_en.updated_at,
+							   n_en.id,
+							   n_en.type,
+							   n_en.title,
+							   COALESCE(array_agg(n.language ORDER BY n.language) FILTER (WHERE n.language IS NOT NULL), '{}') AS existing,
+							   COALESCE(array_agg(n.language ORDER BY n.language) FILTER (WHERE n.updated_at < n_en.updated_at), '{}') AS stale
+						FROM news n_en
+							LEFT JOIN news n
+								   ON n.id = n_en.id
+								  AND n.language != n_en.language
+						WHERE n_en.language = '%[1]v'
+						GROUP BY n_en.language, n_en.id
+						ORDER BY n_en.created_at DESC
+						LIMIT $1 OFFSET $2`, fallbackLanguage)
+	result, err := storage.Select[TranslationCoverage](ctx, r.db, sql, int64(limit), int64(offset))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select translation coverage for limit:%v,offset:%v", limit, offset)
+	}
+	for _, coverage := range result {
+		coverage.Missing = missingLanguages(expectedLanguages, coverage.Existing)
+	}
+
+	return result, nil
+}
+
+func (r *repository) expectedTranslationLanguages(ctx context.Context, languages []string) ([]string, error) {
+	if len(languages) != 0 {
+		expected := make([]string, 0, len(languages))
+		for _, language := range languages {
+			if language = strings.ToLower(strings.TrimSpace(language)); language != "" && language != fallbackLanguage {
+				expected = append(expected, language)
+			}
+		}
+
+		return expected, nil
+	}
+	type newsLanguage struct {
+		Language string
+	}
+	sql := `SELECT DISTINCT language FROM news WHERE language != $1 ORDER BY language`
+	result, err := storage.Select[newsLanguage](ctx, r.db, sql, fallbackLanguage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select distinct news languages")
+	}
+	expected := make([]string, 0, len(result))
+	for _, elem := range result {
+		expected = append(expected, elem.Language)
+	}
+
+	return expected, nil
+}
+
+func missingLanguages(expected, existing []string) []string {
+	existingLanguages := make(map[string]struct{}, len(existing))
+	for _, language := range existing {
+		existingLanguages[language] = struct{}{}
+	}
+	missing := make([]string, 0, len(expected))
+	for _, language := range expected {
+		if _, found := existingLanguages[language]; !found {
+			missing = append(missing, language)
+		}
+	}
+
+	return missing
+}
+
+// fallbackChain returns the languages, by priority, that a news article is looked up in, when requested in the provided language.
+// `en` is not part of it, because it's always the last resort.
+func (cfg *config) fallbackChain(language string) []string {
+	chain := make([]string, 0, 1+len(cfg.FallbackLanguages[language]))
+	chain = append(chain, language)
+	for _, fallback := range cfg.FallbackLanguages[language] {
+		if fallback = strings.ToLower(fallback); fallback != language && fallback != fallbackLanguage {
+			chain = append(chain, fallback)
+		}
+	}
+
+	return chain
+}
@@ -18,6 +18,9 @@ CREATE TABLE IF NOT EXISTS users  (
                     phone_number_hash                       TEXT,
                     language                                TEXT NOT NULL default 'en'
                   );
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS users_referred_by_ix ON users (referred_by);
 --************************************************************************************************************************************
 -- sent_notifications
 CREATE TABLE IF NOT EXISTS sent_notifications  (
@@ -49,4 +52,101 @@ CREATE TABLE IF NOT EXISTS device_metadata (
                     push_notification_token     TEXT,
                     primary key(user_id, device_unique_id));
 
-ALTER TABLE device_metadata DROP CONSTRAINT IF EXISTS device_metadata_user_id_fkey;
\ No newline at end of file
+ALTER TABLE device_metadata DROP CONSTRAINT IF EXISTS device_metadata_user_id_fkey;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS app_version TEXT;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS tz TEXT;
+--************************************************************************************************************************************
+-- notification_type_toggles
+CREATE TABLE IF NOT EXISTS notification_type_toggles (
+                    updated_at                  TIMESTAMP NOT NULL,
+                    user_id                     TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                    notification_channel        TEXT NOT NULL,
+                    notification_type           TEXT NOT NULL,
+                    enabled                     BOOLEAN NOT NULL,
+                    primary key(user_id,notification_channel,notification_type));
+--************************************************************************************************************************************
+-- notification_domain_toggles
+CREATE TABLE IF NOT EXISTS notification_domain_toggles (
+                    updated_at                  TIMESTAMP NOT NULL,
+                    user_id                     TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                    notification_channel        TEXT NOT NULL,
+                    notification_domain         TEXT NOT NULL,
+                    enabled                     BOOLEAN NOT NULL,
+                    primary key(user_id,notification_channel,notification_domain));
+-- Migrates the toggles from the old `users.disabled_<channel>_notification_domains` columns, where `all` meant `disable_all`.
+-- The columns are kept for one more release, so that the instances still running the previous one keep working during rolling deploys.
+-- It's safe to rerun it, because the toggles changed since then are never overwritten.
+DO $$
+BEGIN
+    IF EXISTS(SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'disabled_push_notification_domains') THEN
+        INSERT INTO notification_domain_toggles (updated_at, user_id, notification_channel, notification_domain, enabled)
+            SELECT timezone('utc', now()), u.user_id, 'push', (CASE WHEN d.domain = 'all' THEN 'disable_all' ELSE d.domain END), d.domain = 'all'
+            FROM users u, unnest(u.disabled_push_notification_domains) d(domain)
+            WHERE COALESCE(d.domain, '') != ''
+        ON CONFLICT DO NOTHING;
+    END IF;
+    IF EXISTS(SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'disabled_email_notification_domains') THEN
+        INSERT INTO notification_domain_toggles (updated_at, user_id, notification_channel, notification_domain, enabled)
+            SELECT timezone('utc', now()), u.user_id, 'email', (CASE WHEN d.domain = 'all' THEN 'disable_all' ELSE d.domain END), d.domain = 'all'
+            FROM users u, unnest(u.disabled_email_notification_domains) d(domain)
+            WHERE COALESCE(d.domain, '') != ''
+        ON CONFLICT DO NOTHING;
+    END IF;
+END $$;
+--************************************************************************************************************************************
+-- email_suppressions
+CREATE TABLE IF NOT EXISTS email_suppressions (
+                    created_at                  TIMESTAMP NOT NULL,
+                    updated_at                  TIMESTAMP NOT NULL,
+                    email                       TEXT NOT NULL PRIMARY KEY,
+                    reason                      TEXT NOT NULL);
+--************************************************************************************************************************************
+-- push_notification_templates
+CREATE TABLE IF NOT EXISTS push_notification_templates (
+                    created_at                  TIMESTAMP NOT NULL,
+                    version                     BIGINT NOT NULL,
+                    notification_type           TEXT NOT NULL,
+                    language                    TEXT NOT NULL,
+                    created_by                  TEXT NOT NULL,
+                    title                       TEXT NOT NULL,
+                    body                        TEXT NOT NULL,
+                    primary key(notification_type,language,version));
+--************************************************************************************************************************************
+-- published_push_notification_templates
+CREATE TABLE IF NOT EXISTS published_push_notification_templates (
+                    published_at                TIMESTAMP NOT NULL,
+                    version                     BIGINT NOT NULL,
+                    notification_type           TEXT NOT NULL,
+                    language                    TEXT NOT NULL,
+                    published_by                TEXT NOT NULL,
+                    FOREIGN KEY(notification_type,language,version) REFERENCES push_notification_templates(notification_type,language,version) ON DELETE CASCADE,
+                    primary key(notification_type,language));
+--************************************************************************************************************************************
+-- captured_notifications
+CREATE TABLE IF NOT EXISTS captured_notifications (
+                    captured_at                 TIMESTAMP NOT NULL,
+                    id                          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+                    notification_channel        TEXT NOT NULL,
+                    target                      TEXT NOT NULL,
+                    payload                     JSONB NOT NULL);
+CREATE INDEX IF NOT EXISTS captured_notifications_captured_at_ix ON captured_notifications (captured_at);
+CREATE INDEX IF NOT EXISTS captured_notifications_lookup_ix ON captured_notifications (notification_channel,target,captured_at);
+--************************************************************************************************************************************
+-- mining_sessions
+CREATE TABLE IF NOT EXISTS mining_sessions (
+                    started_at                  TIMESTAMP NOT NULL,
+                    ended_at                    TIMESTAMP NOT NULL,
+                    expiring_reminder_sent_at   TIMESTAMP,
+                    expired_reminder_sent_at    TIMESTAMP,
+                    reminders_deferred_until    TIMESTAMP,
+                    user_id                     TEXT NOT NULL PRIMARY KEY);
+CREATE INDEX IF NOT EXISTS mining_sessions_ended_at_ix ON mining_sessions (ended_at);
+--************************************************************************************************************************************
+-- user_activity
+CREATE TABLE IF NOT EXISTS user_activity (
+                    last_active_at                  TIMESTAMP NOT NULL,
+                    re_engagement_deferred_until    TIMESTAMP,
+                    re_engagement_steps_sent        SMALLINT NOT NULL DEFAULT 0,
+                    user_id                         TEXT NOT NULL PRIMARY KEY);
+CREATE INDEX IF NOT EXISTS user_activity_re_engagement_ix ON user_activity (re_engagement_steps_sent,last_active_at);
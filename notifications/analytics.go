@@ -13,6 +13,9 @@ import (
 )
 
 func (r *repository) sendAnalyticsSetUserAttributesCommandMessage(ctx context.Context, cmd *analytics.SetUserAttributesCommand) error {
+	if testNotificationRunFrom(ctx) != nil {
+		return nil
+	}
 	valueBytes, err := json.MarshalContext(ctx, cmd)
 	if err != nil {
 		return errors.Wrapf(err, "failed to marshal %#v", cmd)
@@ -31,6 +34,9 @@ func (r *repository) sendAnalyticsSetUserAttributesCommandMessage(ctx context.Co
 }
 
 func (r *repository) sendAnalyticsTrackActionCommandMessage(ctx context.Context, cmd *analytics.TrackActionCommand) error {
+	if testNotificationRunFrom(ctx) != nil {
+		return nil
+	}
 	valueBytes, err := json.MarshalContext(ctx, cmd)
 	if err != nil {
 		return errors.Wrapf(err, "failed to marshal %#v", cmd)
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+//nolint:gochecknoglobals // It's a stateless singleton.
+var appVersionRegex = regexp.MustCompile(`^\d+(\.\d+){0,2}`)
+
+func (r *repository) GetAudience(ctx context.Context, userID string) (*Audience, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	type audience struct {
+		CreatedAt    *time.Time
+		Country      string
+		AppVersion   string
+		HasReferrals bool
+	}
+	sql := `SELECT u.created_at,
+				   u.country,
+				   COALESCE((SELECT dm.app_version
+							 FROM device_metadata dm
+							 WHERE dm.user_id = u.user_id
+							   AND dm.app_version IS NOT NULL
+							   AND dm.app_version != ''
+							 ORDER BY dm.updated_at DESC NULLS LAST
+							 LIMIT 1), '') AS app_version,
+				   EXISTS(SELECT 1 FROM users r WHERE r.referred_by = u.user_id AND r.user_id != u.user_id) AS has_referrals
+			FROM users u
+			WHERE u.user_id = $1`
+	resp, err := storage.Get[audience](ctx, r.db, sql, userID)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return &Audience{Segments: users.Enum[AudienceSegment]{}}, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to select audience for userID:%v", userID)
+	}
+	aud := &Audience{
+		Segments:   make(users.Enum[AudienceSegment], 0, len(AllAudienceSegments)),
+		Country:    resp.Country,
+		AppVersion: NormalizeAppVersion(resp.AppVersion),
+	}
+	if resp.HasReferrals {
+		aud.Segments = append(aud.Segments, HasReferralsAudienceSegment)
+	}
+	if resp.CreatedAt != nil && !resp.CreatedAt.IsNil() && resp.CreatedAt.After(*r.newUserAudienceThreshold().Time) {
+		aud.Segments = append(aud.Segments, NewUserAudienceSegment)
+	}
+
+	return aud, nil
+}
+
+// NormalizeAppVersion strips everything after the `major.minor.patch` part (build numbers, suffixes, etc.).
+func NormalizeAppVersion(version string) string {
+	return appVersionRegex.FindString(strings.TrimSpace(strings.TrimPrefix(version, "v")))
+}
+
+func (t *Targeting) IsEmpty() bool {
+	return t == nil ||
+		((t.Countries == nil || len(*t.Countries) == 0) &&
+			(t.Segments == nil || len(*t.Segments) == 0) &&
+			t.MinAppVersion == "" &&
+			t.MaxAppVersion == "")
+}
+
+func (t *Targeting) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.Countries != nil {
+		for _, country := range *t.Countries {
+			if len(country) != 2 || strings.ToUpper(country) != country { //nolint:gomnd // ISO 3166-1 alpha-2.
+				return errors.Errorf("invalid country `%v`, expected an ISO 3166-1 alpha-2 uppercase code", country)
+			}
+		}
+	}
+	if t.Segments != nil {
+		for _, segment := range *t.Segments {
+			found := false
+			for _, allowed := range AllAudienceSegments {
+				found = found || allowed == segment
+			}
+			if !found {
+				return errors.Errorf("invalid segment `%v`, allowed: %v", segment, AllAudienceSegments)
+			}
+		}
+	}
+	for _, version := range []string{t.MinAppVersion, t.MaxAppVersion} {
+		if version != "" && NormalizeAppVersion(version) != version {
+			return errors.Errorf("invalid app version `%v`, expected `major[.minor[.patch]]`", version)
+		}
+	}
+	if t.MinAppVersion != "" && t.MaxAppVersion != "" && compareAppVersions(t.MinAppVersion, t.MaxAppVersion) > 0 {
+		return errors.Errorf("minAppVersion `%v` is greater than maxAppVersion `%v`", t.MinAppVersion, t.MaxAppVersion)
+	}
+
+	return nil
+}
+
+// Matches has to be kept in sync with the `news_targeting_matches` SQL function.
+func (t *Targeting) Matches(audience *Audience) bool { //nolint:gocognit // .
+	if t.IsEmpty() {
+		return true
+	}
+	if audience == nil {
+		return false
+	}
+	if t.Countries != nil && len(*t.Countries) != 0 {
+		found := false
+		for _, country := range *t.Countries {
+			found = found || strings.EqualFold(country, audience.Country)
+		}
+		if !found {
+			return false
+		}
+	}
+	if t.Segments != nil && len(*t.Segments) != 0 {
+		found := false
+		for _, segment := range *t.Segments {
+			for _, userSegment := range audience.Segments {
+				found = found || segment == userSegment
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if (t.MinAppVersion != "" || t.MaxAppVersion != "") && audience.AppVersion == "" {
+		return false
+	}
+
+	return (t.MinAppVersion == "" || compareAppVersions(audience.AppVersion, t.MinAppVersion) >= 0) &&
+		(t.MaxAppVersion == "" || compareAppVersions(audience.AppVersion, t.MaxAppVersion) <= 0)
+}
+
+func compareAppVersions(left, right string) int {
+	const parts = 3
+	lParts, rParts := strings.Split(left, "."), strings.Split(right, ".")
+	for i := 0; i < parts; i++ {
+		var l, r uint64
+		if i < len(lParts) {
+			l, _ = strconv.ParseUint(lParts[i], 10, 64) //nolint:errcheck // Not needed, we default to 0.
+		}
+		if i < len(rParts) {
+			r, _ = strconv.ParseUint(rParts[i], 10, 64) //nolint:errcheck // Not needed, we default to 0.
+		}
+		if l != r {
+			if l < r {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (r *repository) newUserAudienceThreshold() *time.Time {
+	return time.New(time.Now().Add(-r.cfg.NewUserAudiencePeriod))
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"math/rand"
+	stdlibtime "time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/email"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/notifications/inapp"
+	"github.com/ice-blockchain/wintr/notifications/push"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	defaultCapturedNotificationsRetention = 7 * 24 * stdlibtime.Hour
+)
+
+type (
+	// | notificationsCapturer stores the notifications that would have been sent, in capture mode.
+	notificationsCapturer struct {
+		db *storage.DB
+	}
+	capturingPushClient struct {
+		*notificationsCapturer
+	}
+	capturingEmailClient struct {
+		*notificationsCapturer
+	}
+	capturingInAppClient struct {
+		*notificationsCapturer
+		// The in-app feed it replaces.
+		feed string
+	}
+)
+
+func (r *repository) GetCapturedNotifications(
+	ctx context.Context, channel NotificationChannel, target string, limit, offset uint64,
+) ([]*CapturedNotification, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `SELECT captured_at,
+				   payload,
+				   notification_channel,
+				   target,
+				   id
+			FROM captured_notifications
+			WHERE ($1 = '' OR notification_channel = $1)
+			  AND ($2 = '' OR target = $2)
+			ORDER BY captured_at DESC, id DESC
+			LIMIT $3 OFFSET $4`
+	resp, err := storage.Select[CapturedNotification](ctx, r.db, sql, channel, target, limit, offset)
+
+	return resp, errors.Wrapf(err, "failed to select captured notifications for channel:%v, target:%v", channel, target)
+}
+
+// useCapturingSinks replaces the clients that would send the notifications, so that they're just stored in captured_notifications.
+func (p *processor) useCapturingSinks() {
+	if p.cfg.CaptureMode.Retention == 0 {
+		p.cfg.CaptureMode.Retention = defaultCapturedNotificationsRetention
+	}
+	capturer := &notificationsCapturer{db: p.db}
+	p.pushNotificationsClient = &capturingPushClient{notificationsCapturer: capturer}
+	p.emailClient = &capturingEmailClient{notificationsCapturer: capturer}
+	p.personalInAppFeed = &capturingInAppClient{notificationsCapturer: capturer, feed: "notifications"}
+	p.globalInAppFeed = &capturingInAppClient{notificationsCapturer: capturer, feed: "announcements"}
+}
+
+func (c *capturingPushClient) Send(ctx context.Context, pn *push.Notification[push.DeviceToken], responder chan<- error) {
+	responder <- c.capture(ctx, PushNotificationChannel, string(pn.Target), pn)
+}
+
+func (c *capturingPushClient) Broadcast(ctx context.Context, pn *push.Notification[push.SubscriptionTopic]) error {
+	return c.capture(ctx, PushNotificationChannel, string(pn.Target), pn)
+}
+
+func (c *capturingPushClient) BroadcastDelayed(ctx context.Context, pn *push.DelayedNotification) error {
+	return c.capture(ctx, PushNotificationChannel, string(pn.Target), pn)
+}
+
+func (*capturingPushClient) Close() error {
+	return nil
+}
+
+func (c *capturingEmailClient) Send(ctx context.Context, parcel *email.Parcel, participants ...email.Participant) error {
+	for _, participant := range participants {
+		if err := c.capture(ctx, EmailNotificationChannel, participant.Email, parcel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (*capturingInAppClient) CreateUserToken(context.Context, inapp.UserID) (*inapp.Token, error) {
+	return new(inapp.Token), nil
+}
+
+func (c *capturingInAppClient) Send(ctx context.Context, parcel *inapp.Parcel, userIDs ...inapp.UserID) error {
+	if len(userIDs) == 0 {
+		return c.capture(ctx, InAppNotificationChannel, c.feed, parcel)
+	}
+	for _, userID := range userIDs {
+		if err := c.capture(ctx, InAppNotificationChannel, userID, parcel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *notificationsCapturer) capture(ctx context.Context, channel NotificationChannel, target string, notification any) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", notification)
+	}
+	sql := `INSERT INTO captured_notifications (captured_at, notification_channel, target, payload) VALUES ($1, $2, $3, $4)`
+	_, err = storage.Exec(ctx, c.db, sql, time.Now().Time, channel, target, string(payload))
+
+	return errors.Wrapf(err, "failed to capture %v notification for %v: %v", channel, target, string(payload))
+}
+
+func (p *processor) startOldCapturedNotificationsCleaner(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(stdlibtime.Duration(1+rand.Intn(24)) * stdlibtime.Minute) //nolint:gosec,gomnd // Not an  issue.
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 30 * stdlibtime.Second
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.deleteOldCapturedNotifications(reqCtx), "failed to deleteOldCapturedNotifications"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *processor) deleteOldCapturedNotifications(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `DELETE FROM captured_notifications WHERE captured_at < $1`
+	if _, err := storage.Exec(ctx, p.db, sql, stdlibtime.Now().Add(-p.cfg.CaptureMode.Retention)); err != nil {
+		return errors.Wrap(err, "failed to delete old data from captured_notifications")
+	}
+
+	return nil
+}
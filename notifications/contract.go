@@ -6,6 +6,7 @@ import (
 	"context"
 	"embed"
 	"io"
+	"sync/atomic"
 	stdlibtime "time"
 
 	"github.com/pkg/errors"
@@ -48,24 +49,54 @@ const (
 )
 
 const (
-	AdoptionChangedNotificationType     NotificationType = "adoption_changed"
-	DailyBonusNotificationType          NotificationType = "daily_bonus"
-	NewContactNotificationType          NotificationType = "new_contact"
-	NewReferralNotificationType         NotificationType = "new_referral"
-	NewsAddedNotificationType           NotificationType = "news_added"
-	PingNotificationType                NotificationType = "ping"
-	LevelBadgeUnlockedNotificationType  NotificationType = "level_badge_unlocked"
-	CoinBadgeUnlockedNotificationType   NotificationType = "coin_badge_unlocked"
-	SocialBadgeUnlockedNotificationType NotificationType = "social_badge_unlocked"
-	RoleChangedNotificationType         NotificationType = "role_changed"
-	LevelChangedNotificationType        NotificationType = "level_changed"
+	AdoptionChangedNotificationType       NotificationType = "adoption_changed"
+	DailyBonusNotificationType            NotificationType = "daily_bonus"
+	NewContactNotificationType            NotificationType = "new_contact"
+	NewReferralNotificationType           NotificationType = "new_referral"
+	NewsAddedNotificationType             NotificationType = "news_added"
+	PingNotificationType                  NotificationType = "ping"
+	LevelBadgeUnlockedNotificationType    NotificationType = "level_badge_unlocked"
+	CoinBadgeUnlockedNotificationType     NotificationType = "coin_badge_unlocked"
+	SocialBadgeUnlockedNotificationType   NotificationType = "social_badge_unlocked"
+	RoleChangedNotificationType           NotificationType = "role_changed"
+	LevelChangedNotificationType          NotificationType = "level_changed"
+	MiningSessionExpiringNotificationType NotificationType = "mining_session_expiring"
+	MiningSessionExpiredNotificationType  NotificationType = "mining_session_expired"
+	ReEngagementStep1NotificationType     NotificationType = "re_engagement_step_1"
+	ReEngagementStep2NotificationType     NotificationType = "re_engagement_step_2"
+	ReEngagementStep3NotificationType     NotificationType = "re_engagement_step_3"
+)
+
+const (
+	BounceEmailEventType    EmailEventType = "bounce"
+	ComplaintEmailEventType EmailEventType = "complaint"
+)
+
+const (
+	ErrorTranslationProblemSeverity   TranslationProblemSeverity = "error"
+	WarningTranslationProblemSeverity TranslationProblemSeverity = "warning"
+)
+
+const (
+	InCooldownUserPingSkipReason         UserPingSkipReason = "in_cooldown"
+	LimitReachedUserPingSkipReason       UserPingSkipReason = "limit_reached"
+	ConcurrentlyPingedUserPingSkipReason UserPingSkipReason = "concurrently_pinged"
+)
+
+const (
+	HasReferralsAudienceSegment AudienceSegment = "has_referrals"
+	NewUserAudienceSegment      AudienceSegment = "new_user"
 )
 
 var (
-	ErrNotFound              = storage.ErrNotFound
-	ErrDuplicate             = storage.ErrDuplicate
-	ErrRelationNotFound      = storage.ErrRelationNotFound
-	ErrPingingUserNotAllowed = errors.New("pinging user is not allowed")
+	ErrNotFound                        = storage.ErrNotFound
+	ErrDuplicate                       = storage.ErrDuplicate
+	ErrRelationNotFound                = storage.ErrRelationNotFound
+	ErrPingingUserNotAllowed           = errors.New("pinging user is not allowed")
+	ErrInvalidEmailUnsubscribeToken    = errors.New("invalid email unsubscribe token")
+	ErrInvalidEmailEventsSecret        = errors.New("invalid email events webhook secret")
+	ErrInvalidPushNotificationTemplate = errors.New("invalid push notification template")
+	ErrTestNotificationNotSupported    = errors.New("test notifications are not supported for the notification type")
 	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
 	AllNotificationChannels = users.Enum[NotificationChannel]{
 		PushOrFallbackToEmailOrFallbackToAnalyticsNotificationChannel,
@@ -90,6 +121,42 @@ var (
 		SocialBadgeUnlockedNotificationType,
 		RoleChangedNotificationType,
 		LevelChangedNotificationType,
+		MiningSessionExpiringNotificationType,
+		MiningSessionExpiredNotificationType,
+		ReEngagementStep1NotificationType,
+		ReEngagementStep2NotificationType,
+		ReEngagementStep3NotificationType,
+	}
+	// ReEngagementNotificationTypes are the notification types of the re-engagement steps, in order.
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	ReEngagementNotificationTypes = []NotificationType{
+		ReEngagementStep1NotificationType,
+		ReEngagementStep2NotificationType,
+		ReEngagementStep3NotificationType,
+	}
+	// NotificationTypeDomains are the domains of the notification types that can be toggled individually, on top of their domain.
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	NotificationTypeDomains = map[NotificationType]NotificationDomain{
+		DailyBonusNotificationType:            DailyBonusNotificationDomain,
+		NewContactNotificationType:            MicroCommunityNotificationDomain,
+		NewReferralNotificationType:           MicroCommunityNotificationDomain,
+		NewsAddedNotificationType:             NewsNotificationDomain,
+		PingNotificationType:                  MicroCommunityNotificationDomain,
+		LevelBadgeUnlockedNotificationType:    AchievementsNotificationDomain,
+		CoinBadgeUnlockedNotificationType:     AchievementsNotificationDomain,
+		SocialBadgeUnlockedNotificationType:   AchievementsNotificationDomain,
+		RoleChangedNotificationType:           AchievementsNotificationDomain,
+		LevelChangedNotificationType:          AchievementsNotificationDomain,
+		MiningSessionExpiringNotificationType: MiningNotificationDomain,
+		MiningSessionExpiredNotificationType:  MiningNotificationDomain,
+		ReEngagementStep1NotificationType:     PromotionsNotificationDomain,
+		ReEngagementStep2NotificationType:     PromotionsNotificationDomain,
+		ReEngagementStep3NotificationType:     PromotionsNotificationDomain,
+	}
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	AllAudienceSegments = users.Enum[AudienceSegment]{
+		HasReferralsAudienceSegment,
+		NewUserAudienceSegment,
 	}
 	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
 	AllNotificationDomains = map[NotificationChannel][]NotificationDomain{
@@ -123,27 +190,194 @@ type (
 	NotificationChannel             string
 	NotificationDomain              string
 	NotificationType                string
+	AudienceSegment                 string
+	EmailEventType                  string
+	TranslationProblemSeverity      string
+	UserPingSkipReason              string
 	NotificationChannels            struct {
 		NotificationChannels *users.Enum[NotificationChannel] `json:"notificationChannels,omitempty" swaggertype:"array,string" enums:"inapp,sms,email,push,analytics,push||analytics,push||email,push||email||analytics"` //nolint:lll // .
 	}
+	// Targeting restricts who is allowed to see/be notified about something. All specified rules have to match.
+	Targeting struct {
+		// ISO 3166-1 alpha-2 country codes. The user's country has to be one of them.
+		Countries *users.Enum[string] `json:"countries,omitempty" example:"US,RO"`
+		// The user has to be part of at least one of them.
+		Segments *users.Enum[AudienceSegment] `json:"segments,omitempty" swaggertype:"array,string" enums:"has_referrals,new_user"`
+		// Inclusive.
+		MinAppVersion string `json:"minAppVersion,omitempty" example:"1.2.0"`
+		// Inclusive.
+		MaxAppVersion string `json:"maxAppVersion,omitempty" example:"2.0.0"`
+	}
+	// Audience holds everything we know about a user that is relevant for Targeting.
+	Audience struct {
+		Segments   users.Enum[AudienceSegment] `json:"segments,omitempty"`
+		Country    string                      `json:"country,omitempty"`
+		AppVersion string                      `json:"appVersion,omitempty"`
+	}
+	// AnnouncementStats is how many announcements were sent via a channel, to users of a language, in a day.
+	// Announcements broadcasted via push topics are counted once per topic, not per recipient.
+	AnnouncementStats struct {
+		Day                 *time.Time          `json:"day" example:"2022-01-03T00:00:00Z"`
+		Language            string              `json:"language" example:"en"`
+		NotificationChannel NotificationChannel `json:"notificationChannel" example:"push"`
+		Sent                uint64              `json:"sent" example:"123"`
+	}
 	NotificationChannelToggle struct {
 		Type    NotificationDomain `json:"type" example:"system"`
 		Enabled bool               `json:"enabled" example:"true"`
+		// The notification types of the domain, which can be toggled individually, overriding the domain toggle.
+		NotificationTypes []*NotificationTypeToggle `json:"notificationTypes,omitempty"`
+	}
+	NotificationTypeToggle struct {
+		Type NotificationType `json:"type" example:"ping"`
+		// Either the override, if any, or the domain toggle.
+		Enabled bool `json:"enabled" example:"true"`
+		// Whether it's toggled individually, rather than inheriting the domain toggle.
+		Overridden bool `json:"overridden" example:"false"`
+	}
+	// EmailEvent is a provider agnostic bounce/complaint event, for an email address.
+	EmailEvent struct {
+		OccurredAt *time.Time     `json:"occurredAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		Email      string         `json:"email" example:"jdoe@example.com"`
+		Type       EmailEventType `json:"type" example:"bounce" enums:"bounce,complaint"`
+		// Only for bounces. Temporary (soft) bounces are ignored.
+		Permanent bool `json:"permanent,omitempty" example:"true"`
+	}
+	// TranslationProblem is something wrong with the template, of a notification type, in a language, as found by LintTranslations.
+	TranslationProblem struct {
+		NotificationType NotificationType
+		Language         string
+		// `title` or `body`, if it's about just one of them.
+		Field    string
+		Message  string
+		Severity TranslationProblemSeverity
+	}
+	// TranslationLintOptions are the limits of the rendered push notifications, as shown by the devices, in characters.
+	TranslationLintOptions struct {
+		MaxTitleLength int
+		MaxBodyLength  int
+	}
+	// PushNotificationTemplate is a version of the push notification template, of a notification type, in a language.
+	// Once published, it's used instead of the template the services are built with (which is version 0).
+	PushNotificationTemplate struct {
+		CreatedAt *time.Time `json:"createdAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		// Only for the published version.
+		PublishedAt      *time.Time       `json:"publishedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		NotificationType NotificationType `json:"notificationType" example:"ping"`
+		Language         string           `json:"language" example:"en"`
+		CreatedBy        string           `json:"createdBy,omitempty" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
+		// ICU MessageFormat.
+		Title string `json:"title" example:"{Username} pinged you"`
+		// ICU MessageFormat.
+		Body    string `json:"body" example:"Start mining, to not lose your streak!"`
+		Version uint64 `json:"version" example:"1"`
+		// Whether it's the version that's used.
+		Published bool `json:"published" example:"true"`
+	}
+	// PushNotificationTemplatePreview is how a push notification template is rendered, for some data.
+	PushNotificationTemplatePreview struct {
+		Data  map[string]any `json:"data"`
+		Title string         `json:"title" example:"@jdoe pinged you"`
+		Body  string         `json:"body" example:"Start mining, to not lose your streak!"`
+	}
+	// TestNotification is what the source of a notification type renders for a user, via every channel, for some sample data.
+	TestNotification struct {
+		Push  []*push.Notification[push.DeviceToken] `json:"push,omitempty"`
+		InApp []*inapp.Parcel                        `json:"inApp,omitempty"`
+		Email []*TestEmail                           `json:"email,omitempty"`
+		// Whether it was sent to the user too, rather than just rendered.
+		Sent bool `json:"sent" example:"false"`
+	}
+	TestEmail struct {
+		To      string `json:"to" example:"jdoe@example.com"`
+		Subject string `json:"subject" example:"Your daily bonus is available"`
+		// HTML, with the unsubscribe links.
+		Body string `json:"body" example:"<p>Your daily bonus is available</p>"`
+	}
+	// CapturedNotification is a notification that the processor would have sent, if it wasn't in capture mode.
+	CapturedNotification struct {
+		CapturedAt *time.Time `json:"capturedAt" example:"2022-01-03T16:20:52.156534Z"`
+		// The push notification, the email or the in-app parcel, exactly as it was handed over to be sent.
+		Payload map[string]any `json:"payload"`
+		// It's `push`, `email` or `inapp`.
+		NotificationChannel NotificationChannel `json:"notificationChannel" example:"push"`
+		// The device token or the subscription topic, for `push`; the email address, for `email`; the user ID or the feed, for `inapp`.
+		Target string `json:"target" example:"jdoe@example.com"`
+		ID     uint64 `json:"id" example:"1"`
 	}
 	UserPing struct {
 		LastPingCooldownEndedAt *time.Time `json:"lastPingCooldownEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
 		UserID                  string     `json:"userId,omitempty" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
 		PingedBy                string     `json:"pingedBy,omitempty" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
 	}
+	// UserPingResult is the outcome of pinging one of the referrals, when pinging all of them at once.
+	// The SkipReason of the ones that weren't even tried to be pinged is that they're in their ping cooldown, that too many were pinged at once
+	// or that they're being pinged by another request. The last two can be pinged again right away.
+	UserPingResult struct {
+		// When the user can be pinged again. It's the previous one, if they weren't pinged.
+		LastPingCooldownEndedAt *time.Time         `json:"lastPingCooldownEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		UserID                  string             `json:"userId" example:"edfd8c02-75e0-4687-9ac2-1ce4723865c4"`
+		Error                   string             `json:"error,omitempty" example:"failed to send message to broker"`
+		SkipReason              UserPingSkipReason `json:"skipReason,omitempty" enums:"in_cooldown,limit_reached,concurrently_pinged" example:"in_cooldown"`
+		Pinged                  bool               `json:"pinged" example:"true"`
+	}
 	ReadRepository interface {
 		GetNotificationChannelToggles(ctx context.Context, channel NotificationChannel, userID string) ([]*NotificationChannelToggle, error)
+
+		GetAudience(ctx context.Context, userID string) (*Audience, error)
+
+		GetAnnouncementStats(ctx context.Context, notificationType NotificationType, uniqueness string, from, to *time.Time) ([]*AnnouncementStats, error)
+
+		// GetPushNotificationTemplates returns, for every language, the version of the template of the notification type that's used.
+		GetPushNotificationTemplates(ctx context.Context, notificationType NotificationType) ([]*PushNotificationTemplate, error)
+		// GetPushNotificationTemplateVersions returns every version of the template of the notification type, in the language, newest first.
+		GetPushNotificationTemplateVersions(ctx context.Context, notificationType NotificationType, language string) ([]*PushNotificationTemplate, error)
+		// PreviewPushNotificationTemplate renders a version of the template for the data or, if it's not provided, for sample data.
+		PreviewPushNotificationTemplate(
+			ctx context.Context, notificationType NotificationType, language string, version uint64, data map[string]any,
+		) (*PushNotificationTemplatePreview, error)
+
+		// GetCapturedNotifications returns what the processors, in capture mode, would have sent, newest first.
+		// The channel and the target are optional filters.
+		GetCapturedNotifications(
+			ctx context.Context, channel NotificationChannel, target string, limit, offset uint64,
+		) ([]*CapturedNotification, error)
 	}
 	WriteRepository interface {
 		ToggleNotificationChannelDomain(ctx context.Context, channel NotificationChannel, domain NotificationDomain, enabled bool, userID string) error
+		// SetNotificationChannelToggles atomically sets all the provided domain toggles and, if provided, their notification type toggles
+		// (the ones that are not `overridden` follow their domain toggle again). The ones not provided are left as they are.
+		// It returns the resulting state, just like GetNotificationChannelToggles.
+		SetNotificationChannelToggles(
+			ctx context.Context, channel NotificationChannel, toggles []*NotificationChannelToggle, userID string,
+		) ([]*NotificationChannelToggle, error)
+		// ToggleNotificationChannelNotificationType overrides the domain toggle for a single notification type. Use nil to remove the override.
+		// The `disable_all` toggle still takes precedence over it.
+		ToggleNotificationChannelNotificationType(
+			ctx context.Context, channel NotificationChannel, notificationType NotificationType, enabled *bool, userID string,
+		) error
 
 		GenerateInAppNotificationsUserAuthToken(ctx context.Context, userID string) (*InAppNotificationsUserAuthToken, error)
 
 		PingUser(ctx context.Context, userID string) error
+		// PingReferrals pings, at once, the referrals of the requesting user that aren't in their ping cooldown anymore.
+		// At most `maxReferralsPingedAtOnce` are pinged per call, the rest being eligible for the next one.
+		// The referrals that were skipped are returned as well, with the reason, up to `maxReferralsPingedAtOnce` of them.
+		PingReferrals(ctx context.Context) ([]*UserPingResult, error)
+
+		// ProcessEmailEvents suppresses the email addresses that permanently bounced or complained, so that they're not emailed anymore,
+		// until the user changes the email address. The secret has to match the configured webhook secret.
+		ProcessEmailEvents(ctx context.Context, secret string, events []*EmailEvent) error
+
+		// CreatePushNotificationTemplate adds a new, unpublished, version of the template of the notification type, in the language.
+		// It has to use the same placeholders as the English template the services are built with.
+		CreatePushNotificationTemplate(ctx context.Context, tmpl *PushNotificationTemplate, userID string) (*PushNotificationTemplate, error)
+		// PublishPushNotificationTemplate starts using the version of the template, instead of the current one, which also rolls it back to an older one.
+		// Version 0 goes back to the template the services are built with.
+		PublishPushNotificationTemplate(ctx context.Context, notificationType NotificationType, language string, version uint64, userID string) error
+
+		// UnsubscribeFromEmails disables, for the user the token was signed for, either the emails of the domain the token was signed for, or all of them.
+		UnsubscribeFromEmails(ctx context.Context, token string, all bool) error
 	}
 	Repository interface {
 		io.Closer
@@ -154,6 +388,10 @@ type (
 	Processor interface {
 		Repository
 		CheckHealth(ctx context.Context) error
+		// SendTestNotification renders the notification of the type for the user, exactly as its source does for the data of the message it consumes.
+		// Unless it's a dry run, it's sent to the user too, even if it was already sent, and it's not recorded as sent,
+		// so that it doesn't prevent the real one from being sent later on. No analytics are tracked for it.
+		SendTestNotification(ctx context.Context, notificationType NotificationType, userID string, data map[string]any, dryRun bool) (*TestNotification, error)
 	}
 )
 
@@ -161,7 +399,11 @@ type (
 
 const (
 	applicationYamlKey          = "notifications"
+	defaultLanguage             = "en"
 	requestingUserIDCtxValueKey = "requestingUserIDCtxValueKey"
+	testNotificationCtxValueKey = "testNotificationCtxValueKey"
+	maxReferralsPingedAtOnce    = 1000
+	userPingMessagesBatchSize   = 100
 )
 
 var (
@@ -171,17 +413,39 @@ var (
 	translations embed.FS
 	//nolint:gochecknoglobals // Its loaded once at startup.
 	allPushNotificationTemplates map[NotificationType]map[languageCode]*pushNotificationTemplate
+	// The templates published at runtime, which take precedence over the embedded ones. They're reloaded periodically.
+	//nolint:gochecknoglobals // It's swapped atomically.
+	publishedPushNotificationTemplates atomic.Pointer[map[NotificationType]map[languageCode]*pushNotificationTemplate]
 	//nolint:gochecknoglobals // Its loaded once at startup.
 	internationalizedEmailDisplayNames = map[string]string{
 		"en": "ice: Decentralized Future",
 	}
+	// Deprecated or non-standard language codes and the language code of the template files that have to be used for them.
+	//nolint:gochecknoglobals // It's a static list.
+	languageAliases = map[languageCode]languageCode{
+		"ka":    "ge",
+		"tl":    "fil",
+		"nb":    "no",
+		"nn":    "no",
+		"iw":    "he",
+		"in":    "id",
+		"jw":    "jv",
+		"zh-cn": "zh-hans",
+		"zh-sg": "zh-hans",
+		"zh-tw": "zh-hant",
+		"zh-hk": "zh-hant",
+		"zh-mo": "zh-hant",
+	}
 )
 
 type (
 	languageCode = string
 	user         struct {
-		LastPingCooldownEndedAt          *time.Time                      `json:"lastPingCooldownEndedAt,omitempty"`
-		DisabledPushNotificationDomains  *users.Enum[NotificationDomain] `json:"disabledPushNotificationDomains,omitempty"`
+		LastPingCooldownEndedAt *time.Time `json:"lastPingCooldownEndedAt,omitempty"`
+		CreatedAt               *time.Time `json:"createdAt,omitempty"`
+		// Deprecated: use notification_domain_toggles. It's only kept until the columns are dropped, in the next release.
+		DisabledPushNotificationDomains *users.Enum[NotificationDomain] `json:"disabledPushNotificationDomains,omitempty"`
+		// Deprecated: use notification_domain_toggles. It's only kept until the columns are dropped, in the next release.
 		DisabledEmailNotificationDomains *users.Enum[NotificationDomain] `json:"disabledEmailNotificationDomains,omitempty"`
 		DisabledSMSNotificationDomains   *users.Enum[NotificationDomain] `json:"disabledSMSNotificationDomains,omitempty"` //nolint:tagliatelle // Wrong.
 		PhoneNumber                      string                          `json:"phoneNumber,omitempty"`
@@ -194,6 +458,7 @@ type (
 		ReferredBy                       string                          `json:"referredBy,omitempty"`
 		PhoneNumberHash                  string                          `json:"phoneNumberHash,omitempty"`
 		Language                         string                          `json:"language,omitempty"`
+		Country                          string                          `json:"country,omitempty"`
 		AgendaContactUserIDs             []string                        `json:"agendaContactUserIDs,omitempty" db:"agenda_contact_user_ids"`
 	}
 	userTableSource struct {
@@ -229,6 +494,12 @@ type (
 	agendaContactsSource struct {
 		*processor
 	}
+	miningSessionsTableSource struct {
+		*processor
+	}
+	viewedNewsSource struct {
+		*processor
+	}
 	repository struct {
 		cfg                     *config
 		shutdown                func() error
@@ -243,6 +514,12 @@ type (
 	processor struct {
 		*repository
 	}
+	reEngagementStep struct {
+		// How long the user has to be inactive for, to be sent it.
+		InactiveFor stdlibtime.Duration `yaml:"inactiveFor"`
+		// Either `push` or `email`.
+		Channel NotificationChannel `yaml:"channel"`
+	}
 	notificationDelayConfig struct {
 		MinNotificationDelaySec uint `yaml:"minNotificationDelaySec"`
 		MaxNotificationDelaySec uint `yaml:"maxNotificationDelaySec"`
@@ -255,8 +532,54 @@ type (
 			Levels []string `yaml:"levels"`
 			Roles  []string `yaml:"roles"`
 		} `yaml:"disabledAchievementsNotifications" `
-		messagebroker.Config    `mapstructure:",squash"` //nolint:tagliatelle // Nope.
-		notificationDelayConfig `mapstructure:",squash"`
-		PingCooldown            stdlibtime.Duration `yaml:"pingCooldown"`
+		EmailUnsubscribe struct {
+			// The secret used to sign the unsubscribe tokens. If not set, emails have no unsubscribe links.
+			Secret string `yaml:"secret"`
+			// The URL of the unsubscribe endpoint of `cmd/husky-pack`.
+			URL string `yaml:"url"`
+			// How long the unsubscribe links are valid for. Defaults to 30 days.
+			TokenTTL stdlibtime.Duration `yaml:"tokenTTL"`
+		} `yaml:"emailUnsubscribe"`
+		// The secret the email provider (or any relay in front of it) has to send the bounce/complaint events with. If not set, they're rejected.
+		EmailEventsWebhookSecret string                   `yaml:"emailEventsWebhookSecret"`
+		messagebroker.Config     `mapstructure:",squash"` //nolint:tagliatelle // Nope.
+		notificationDelayConfig  `mapstructure:",squash"`
+		PingCooldown             stdlibtime.Duration `yaml:"pingCooldown"`
+		// How often the published push notification templates are reloaded from the database. Defaults to 1 minute.
+		PushNotificationTemplatesReloadInterval stdlibtime.Duration `yaml:"pushNotificationTemplatesReloadInterval"`
+		NewUserAudiencePeriod                   stdlibtime.Duration `yaml:"newUserAudiencePeriod"`
+		// In capture mode, the push, email and in-app notifications are not sent, but stored in the database, to be checked via GetCapturedNotifications.
+		// I.E. for staging or integration tests.
+		CaptureMode struct {
+			Enabled bool `yaml:"enabled"`
+			// How long the captured notifications are kept for. Defaults to 7 days.
+			Retention stdlibtime.Duration `yaml:"retention"`
+		} `yaml:"captureMode"`
+		// The users are not sent the notifications that husky schedules itself (I.E. the mining session reminders) during the quiet time.
+		// Start and End are since midnight, in the time zone of the device the user updated last, I.E. `22h` and `8h`. It's disabled if they're equal.
+		QuietTime struct {
+			Start stdlibtime.Duration `yaml:"start"`
+			End   stdlibtime.Duration `yaml:"end"`
+		} `yaml:"quietTime"`
+		MiningSessionReminders struct {
+			// How long before the mining session ends the users are reminded to extend it. Defaults to 1 hour.
+			ExpiringIn stdlibtime.Duration `yaml:"expiringIn"`
+			// How long after the mining session ended the users can still be reminded that it did, I.E. if it ended during the quiet time.
+			// Defaults to 12 hours.
+			ExpiredFor stdlibtime.Duration `yaml:"expiredFor"`
+			// How often the mining sessions are checked for reminders to be sent. Defaults to 1 minute.
+			CheckInterval stdlibtime.Duration `yaml:"checkInterval"`
+		} `yaml:"miningSessionReminders"`
+		ReEngagement struct {
+			// The notifications sent to the users that stopped being active (mining, viewing news, pinging), in order of InactiveFor.
+			// Each one has the templates of its own notification type: `re_engagement_step_1`, `re_engagement_step_2`, etc.
+			// They stop as soon as the user is active again. The ones that are already overdue when a user is checked are skipped.
+			Steps []*reEngagementStep `yaml:"steps"`
+			// How often the users are checked for steps to be sent. Defaults to 1 minute.
+			CheckInterval stdlibtime.Duration `yaml:"checkInterval"`
+		} `yaml:"reEngagement"`
+		// If set, the push notifications and the emails are handed over to the in-process fakes of the providers, instead of the real ones.
+		// I.E. for integration tests. Capture mode still takes precedence.
+		FakeProviders bool `yaml:"fakeProviders"`
 	}
 )
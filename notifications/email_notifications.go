@@ -5,6 +5,7 @@ package notifications
 import (
 	"context"
 	"fmt"
+	"html"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
@@ -12,6 +13,7 @@ import (
 
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/email"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 type (
@@ -31,18 +33,35 @@ func (r *repository) sendEmailNotification(ctx context.Context, en *emailNotific
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
 
-	if err := r.insertSentNotification(ctx, en.sn); err != nil {
-		return errors.Wrapf(err, "failed to insert %#v", en.sn)
+	run := testNotificationRunFrom(ctx)
+	if run == nil {
+		if err := r.insertSentNotification(ctx, en.sn); err != nil {
+			return errors.Wrapf(err, "failed to insert %#v", en.sn)
+		}
 	}
 	en.en.From.Email = "no-reply@ice.io"
+	if en.en.Body != nil && en.en.Body.Type == email.TextHTML {
+		domain := NotificationTypeDomains[en.sn.NotificationType]
+		en.en.Body.Data = r.cfg.withEmailUnsubscribeLinks(en.en.Body.Data, en.sn.UserID, domain, time.Now())
+	}
 	if en.en.From.Name = internationalizedEmailDisplayNames[en.sn.Language]; en.en.From.Name == "" {
 		en.en.From.Name = internationalizedEmailDisplayNames["en"]
 	}
+	if run != nil {
+		if run.recordEmail(en.en, en.sn.NotificationChannelValue); !run.Sent {
+			return nil
+		}
+	}
 
 	if err := r.emailClient.Send(ctx, en.en, email.Participant{Name: en.displayName, Email: en.sn.NotificationChannelValue}); err != nil {
+		var rollbackErr error
+		if run == nil {
+			rollbackErr = r.deleteSentNotification(ctx, en.sn)
+		}
+
 		return multierror.Append( //nolint:wrapcheck // .
 			errors.Wrapf(err, "failed to send email notification:%#v, desired to be sent:%#v", en.en, en.sn),
-			errors.Wrapf(r.deleteSentNotification(ctx, en.sn), "failed to delete SENT_NOTIFICATIONS as a rollback for %#v", en.sn),
+			errors.Wrapf(rollbackErr, "failed to delete SENT_NOTIFICATIONS as a rollback for %#v", en.sn),
 		).ErrorOrNil()
 	}
 
@@ -50,27 +69,19 @@ func (r *repository) sendEmailNotification(ctx context.Context, en *emailNotific
 }
 
 func (r *repository) getEmailNotificationParams( //nolint:funlen,revive // .
-	ctx context.Context, domain NotificationDomain, userID string, onlyIfPushDisabled bool,
+	ctx context.Context, notificationType NotificationType, userID string, onlyIfPushDisabled bool,
 ) (*emailNotificationParams, error) {
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
 	sql := fmt.Sprintf(`SELECT u.username AS display_name, 
-							   (CASE WHEN (u.disabled_email_notification_domains IS NULL 
-												OR NOT (u.disabled_email_notification_domains && ARRAY['%[1]v','%[2]v'])
-										  )
+							   (CASE WHEN %[1]v AND NOT %[3]v
 							    		THEN u.email 
 							    		ELSE '' 
 								END) AS email, 
 							   u.language,
 							   u.user_id,
-							   ( ( u.disabled_push_notification_domains IS NOT NULL 
-								   AND (
-										'%[1]v' = ANY(u.disabled_push_notification_domains)
-										OR 
-										'%[2]v' = ANY(u.disabled_push_notification_domains)
-									   )
-								 ) 
+							   ( NOT %[2]v
 								 OR
 								 (  SELECT * 
 									FROM (SELECT FALSE 
@@ -86,10 +97,11 @@ func (r *repository) getEmailNotificationParams( //nolint:funlen,revive // .
 							   ) AS is_push_disabled
 						FROM users u
 						WHERE u.user_id = $1
-						GROUP BY u.user_id`, domain, AllNotificationDomain)
+						GROUP BY u.user_id`, notificationEnabledSQL(EmailNotificationChannel, notificationType), notificationEnabledSQL(PushNotificationChannel, notificationType),
+		emailSuppressedSQL)
 	resp, err := storage.Get[emailNotificationParams](ctx, r.db, sql, userID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to select for emailNotificationParams for `%v`, userID:%v", domain, userID)
+		return nil, errors.Wrapf(err, "failed to select for emailNotificationParams for `%v`, userID:%v", notificationType, userID)
 	}
 	if resp.Email == "" || resp.DisplayName == "" || (onlyIfPushDisabled && !resp.IsPushDisabled) {
 		return nil, nil //nolint:nilnil // .
@@ -98,3 +110,43 @@ func (r *repository) getEmailNotificationParams( //nolint:funlen,revive // .
 
 	return resp, nil
 }
+
+// sendEmailNotificationFromTemplate emails the push notification template of the notification type, unless it was already sent, I.E. before a retry.
+func (r *repository) sendEmailNotificationFromTemplate(
+	ctx context.Context, notificationType NotificationType, uniqueness, userID string, onlyIfPushDisabled bool,
+) error {
+	params, err := r.getEmailNotificationParams(ctx, notificationType, userID, onlyIfPushDisabled)
+	if err != nil || params == nil {
+		return errors.Wrapf(err, "failed to getEmailNotificationParams for notif:%v, userID:%v", notificationType, userID)
+	}
+	tmpl, found := getPushNotificationTemplate(notificationType, params.Language)
+	if !found {
+		return errors.Errorf("language `%v` was not found in the `%v` push config", params.Language, notificationType)
+	}
+	en := &emailNotification{
+		displayName: params.DisplayName,
+		en: &email.Parcel{
+			Body: &email.Body{
+				Type: email.TextHTML,
+				Data: fmt.Sprintf("<p>%v</p>", html.EscapeString(tmpl.getBody(nil))),
+			},
+			Subject: tmpl.getTitle(nil),
+		},
+		sn: &sentNotification{
+			SentAt:   time.Now(),
+			Language: params.Language,
+			sentNotificationPK: sentNotificationPK{
+				UserID:                   userID,
+				Uniqueness:               uniqueness,
+				NotificationType:         notificationType,
+				NotificationChannel:      EmailNotificationChannel,
+				NotificationChannelValue: params.Email,
+			},
+		},
+	}
+	if err = r.sendEmailNotification(ctx, en); err != nil && !storage.IsErr(err, storage.ErrDuplicate) {
+		return errors.Wrapf(err, "failed to sendEmailNotification for notif:%#v", en)
+	}
+
+	return nil
+}
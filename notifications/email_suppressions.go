@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// emailSuppressedSQL is the SQL condition for the email of the user aliased as `u` to be suppressed, because it bounced or complained.
+const emailSuppressedSQL = `EXISTS(SELECT 1 FROM email_suppressions es WHERE es.email = lower(u.email))`
+
+func (r *repository) ProcessEmailEvents(ctx context.Context, secret string, events []*EmailEvent) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	if r.cfg.EmailEventsWebhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(r.cfg.EmailEventsWebhookSecret)) != 1 {
+		return ErrInvalidEmailEventsSecret
+	}
+	// The same row can't be changed twice in the same statement, so the last one wins.
+	reasons := make(map[string]EmailEventType, len(events))
+	for _, event := range events {
+		if !event.suppresses() {
+			continue
+		}
+		reasons[strings.ToLower(strings.TrimSpace(event.Email))] = event.Type
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	args := []any{time.Now().Time}
+	values := make([]string, 0, len(reasons))
+	for email, reason := range reasons {
+		args = append(args, email, reason)
+		values = append(values, fmt.Sprintf("($1,$1,$%v,$%v)", len(args)-1, len(args)))
+	}
+	sql := fmt.Sprintf(`INSERT INTO email_suppressions (CREATED_AT, UPDATED_AT, EMAIL, REASON) VALUES %v
+						ON CONFLICT(EMAIL) DO UPDATE
+						SET REASON = EXCLUDED.REASON,
+							UPDATED_AT = EXCLUDED.UPDATED_AT`, strings.Join(values, ","))
+	if _, err := storage.Exec(ctx, r.db, sql, args...); err != nil {
+		return errors.Wrapf(err, "failed to upsert email_suppressions for %#v", reasons)
+	}
+
+	return nil
+}
+
+// suppresses tells if the email address should not be emailed anymore. Temporary (soft) bounces are retried by the provider itself.
+func (e *EmailEvent) suppresses() bool {
+	if e == nil || strings.TrimSpace(e.Email) == "" {
+		return false
+	}
+	switch e.Type {
+	case ComplaintEmailEventType:
+		return true
+	case BounceEmailEventType:
+		return e.Permanent
+	default:
+		return false
+	}
+}
+
+// clearEmailSuppression is called when an existing user changes their email address, so that we email the new one again.
+func clearEmailSuppression(ctx context.Context, db storage.Execer, email string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `DELETE FROM email_suppressions WHERE email = lower($1)`
+	_, err := storage.Exec(ctx, db, sql, strings.TrimSpace(email))
+
+	return errors.Wrapf(err, "failed to delete email_suppressions for email:%v", email)
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	defaultEmailUnsubscribeTokenTTL = 30 * 24 * stdlibtime.Hour
+	emailUnsubscribeTokenSeparator  = "~~~"
+)
+
+func (r *repository) UnsubscribeFromEmails(ctx context.Context, token string, all bool) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	userID, domain, err := r.cfg.parseEmailUnsubscribeToken(token, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to parse unsubscribe token") // The token is not logged, cuz it's a credential.
+	}
+	toggle := &NotificationChannelToggle{Type: domain, Enabled: false}
+	if all || domain == DisableAllNotificationDomain {
+		toggle = &NotificationChannelToggle{Type: DisableAllNotificationDomain, Enabled: true}
+	}
+
+	return errors.Wrapf(setNotificationChannelToggles(ctx, r.db, EmailNotificationChannel, []*NotificationChannelToggle{toggle}, userID),
+		"failed to unsubscribe userID:%v from %v emails", userID, toggle.Type)
+}
+
+// emailUnsubscribeURL returns the URL that unsubscribes the user from the emails of the domain, or an empty string if it's not configured.
+// If the emails have no domain, the URL unsubscribes the user from all emails.
+func (cfg *config) emailUnsubscribeURL(userID string, domain NotificationDomain, all bool, now *time.Time) string {
+	if cfg.EmailUnsubscribe.URL == "" || cfg.EmailUnsubscribe.Secret == "" {
+		return ""
+	}
+	if domain == "" {
+		domain, all = DisableAllNotificationDomain, true
+	}
+	query := url.Values{"token": []string{cfg.signEmailUnsubscribeToken(userID, domain, now)}}
+	if all {
+		query.Set("all", "true")
+	}
+
+	return fmt.Sprintf("%v?%v", cfg.EmailUnsubscribe.URL, query.Encode())
+}
+
+// withEmailUnsubscribeLinks appends, to the HTML body of the email, the links to unsubscribe from the emails of the domain, or from all of them.
+// The links only open a confirmation page, so that the email scanners that follow them don't unsubscribe anyone.
+// There are no `List-Unsubscribe`/`List-Unsubscribe-Post` (RFC 8058) headers yet, because email.Parcel doesn't support custom headers;
+// that needs wintr/email to support them first.
+func (cfg *config) withEmailUnsubscribeLinks(body, userID string, domain NotificationDomain, now *time.Time) string {
+	domainURL, allURL := cfg.emailUnsubscribeURL(userID, domain, false, now), cfg.emailUnsubscribeURL(userID, domain, true, now)
+	if allURL == "" {
+		return body
+	}
+	if domain == "" {
+		return fmt.Sprintf(`%v<p><a href="%v">Unsubscribe from all emails</a></p>`, body, html.EscapeString(allURL))
+	}
+
+	return fmt.Sprintf(`%v<p><a href="%v">Unsubscribe from these emails</a> | <a href="%v">Unsubscribe from all emails</a></p>`,
+		body, html.EscapeString(domainURL), html.EscapeString(allURL))
+}
+
+// signEmailUnsubscribeToken returns `base64url(userID~~~domain~~~expiresAt).base64url(hmac-sha256(payload))`.
+func (cfg *config) signEmailUnsubscribeToken(userID string, domain NotificationDomain, now *time.Time) string {
+	ttl := cfg.EmailUnsubscribe.TokenTTL
+	if ttl <= 0 {
+		ttl = defaultEmailUnsubscribeTokenTTL
+	}
+	payload := strings.Join([]string{userID, string(domain), strconv.FormatInt(now.Add(ttl).Unix(), 10)}, emailUnsubscribeTokenSeparator)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(cfg.emailUnsubscribeSignature(payload))
+}
+
+func (cfg *config) parseEmailUnsubscribeToken(token string, now *time.Time) (userID string, domain NotificationDomain, err error) {
+	if cfg.EmailUnsubscribe.Secret == "" {
+		return "", "", errors.Wrap(ErrInvalidEmailUnsubscribeToken, "unsubscribe tokens are not configured")
+	}
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return "", "", errors.Wrap(ErrInvalidEmailUnsubscribeToken, "malformed token")
+	}
+	payload, pErr := base64.RawURLEncoding.DecodeString(encodedPayload)
+	signature, sErr := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if pErr != nil || sErr != nil || !hmac.Equal(signature, cfg.emailUnsubscribeSignature(string(payload))) {
+		return "", "", errors.Wrap(ErrInvalidEmailUnsubscribeToken, "invalid signature")
+	}
+	parts := strings.Split(string(payload), emailUnsubscribeTokenSeparator)
+	if len(parts) != 3 { //nolint:gomnd // userID, domain and expiresAt.
+		return "", "", errors.Wrap(ErrInvalidEmailUnsubscribeToken, "malformed payload")
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return "", "", errors.Wrap(ErrInvalidEmailUnsubscribeToken, "expired")
+	}
+
+	return parts[0], NotificationDomain(parts[1]), nil
+}
+
+func (cfg *config) emailUnsubscribeSignature(payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(cfg.EmailUnsubscribe.Secret))
+	mac.Write([]byte(payload)) //nolint:errcheck,revive // It never fails.
+
+	return mac.Sum(nil)
+}
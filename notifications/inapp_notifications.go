@@ -26,18 +26,31 @@ func (r *repository) sendInAppNotification(ctx context.Context, in *inAppNotific
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
+	run := testNotificationRunFrom(ctx)
+	if run != nil {
+		run.recordInApp(in.in)
+	}
 	if true {
 		return nil
 	}
 
-	if err := r.insertSentNotification(ctx, in.sn); err != nil {
+	if run != nil {
+		if !run.Sent {
+			return nil
+		}
+	} else if err := r.insertSentNotification(ctx, in.sn); err != nil {
 		return errors.Wrapf(err, "failed to insert %#v", in.sn)
 	}
 
 	if err := r.personalInAppFeed.Send(ctx, in.in, in.sn.UserID); err != nil {
+		var rollbackErr error
+		if run == nil {
+			rollbackErr = r.deleteSentNotification(ctx, in.sn)
+		}
+
 		return multierror.Append( //nolint:wrapcheck // .
 			errors.Wrapf(err, "failed to send inApp notification:%#v, desired to be sent:%#v", in.in, in.sn),
-			errors.Wrapf(r.deleteSentNotification(ctx, in.sn), "failed to delete SENT_NOTIFICATIONS as a rollback for %#v", in.sn),
+			errors.Wrapf(rollbackErr, "failed to delete SENT_NOTIFICATIONS as a rollback for %#v", in.sn),
 		).ErrorOrNil()
 	}
 
@@ -0,0 +1,609 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+type (
+	// | messageFormat is a parsed ICU MessageFormat pattern, supporting `plural`, `selectordinal`, `select`, `number`, `date` and `time` arguments.
+	// Its literal text can still have `text/template` actions, like `{{.Username}}`, so that the old templates keep working as they are.
+	messageFormat struct {
+		tag      language.Tag
+		language languageCode
+		parts    []*messageFormatPart
+	}
+	messageFormatPart struct {
+		literal  *template.Template
+		branches map[string][]*messageFormatPart
+		text     string
+		arg      string
+		argType  string
+		style    string
+		offset   float64
+		pound    bool
+	}
+	messageFormatParser struct {
+		name    string
+		pattern string
+		pos     int
+	}
+)
+
+const (
+	pluralArgType        = "plural"
+	selectOrdinalArgType = "selectordinal"
+	selectArgType        = "select"
+	numberArgType        = "number"
+	dateArgType          = "date"
+	timeArgType          = "time"
+	otherSelector        = "other"
+	defaultDateLayout    = "02/01/2006"
+	defaultTimeLayout    = "15:04"
+	// Enough for the mining rates, but it hides the float64 noise, I.E. `1.2` instead of `1.2000000000000002`.
+	maxFractionDigits = 9
+)
+
+//nolint:gochecknoglobals // They're static lists.
+var (
+	// Template file language codes that are not valid BCP-47 tags.
+	nonStandardLanguageCodes = map[languageCode]languageCode{
+		"ge": "ka",
+	}
+	pluralFormSelectors = map[plural.Form]string{
+		plural.Zero:  "zero",
+		plural.One:   "one",
+		plural.Two:   "two",
+		plural.Few:   "few",
+		plural.Many:  "many",
+		plural.Other: otherSelector,
+	}
+	// The numeric date layouts (from CLDR) of the languages that don't use `dd/MM/yyyy`.
+	dateLayouts = map[languageCode]string{
+		"en":    "1/2/2006",
+		"en-gb": defaultDateLayout,
+		"en-au": defaultDateLayout,
+		"en-in": defaultDateLayout,
+		"az":    "02.01.2006",
+		"bg":    "02.01.2006",
+		"cs":    "2. 1. 2006",
+		"de":    "02.01.2006",
+		"ge":    "02.01.2006",
+		"ka":    "02.01.2006",
+		"no":    "02.01.2006",
+		"pl":    "02.01.2006",
+		"ro":    "02.01.2006",
+		"ru":    "02.01.2006",
+		"sk":    "2. 1. 2006",
+		"tr":    "02.01.2006",
+		"uk":    "02.01.2006",
+		"nl":    "02-01-2006",
+		"sv":    "2006-01-02",
+		"lt":    "2006-01-02",
+		"hu":    "2006. 01. 02.",
+		"ko":    "2006. 1. 2.",
+		"ja":    "2006/01/02",
+		"zh":    "2006/1/2",
+	}
+	timeLayouts = map[languageCode]string{
+		"en":    "3:04 PM",
+		"en-gb": defaultTimeLayout,
+		"hi":    "3:04 PM",
+		"ko":    "PM 3:04",
+	}
+)
+
+func parseMessageFormat(name string, lang languageCode, pattern string) (*messageFormat, error) {
+	parser := &messageFormatParser{name: name, pattern: pattern}
+	parts, err := parser.parseMessage(false)
+	if err == nil && parser.pos < len(pattern) {
+		err = errors.Errorf("unexpected `}` at %v", parser.pos)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid message format `%v`: %v", name, pattern)
+	}
+	tag := lang
+	if standard, found := nonStandardLanguageCodes[lang]; found {
+		tag = standard
+	}
+
+	return &messageFormat{tag: language.Make(tag), language: lang, parts: parts}, nil
+}
+
+// parseMessage parses until the end of the pattern or until the first unmatched `}`, which is left for the caller.
+func (p *messageFormatParser) parseMessage(inPlural bool) ([]*messageFormatPart, error) { //nolint:gocognit // It's a parser.
+	parts := make([]*messageFormatPart, 0, 1)
+	text := new(strings.Builder)
+	flush := func() error {
+		if text.Len() == 0 {
+			return nil
+		}
+		part, err := p.literal(text.String())
+		if err != nil {
+			return err
+		}
+		parts = append(parts, part)
+		text.Reset()
+
+		return nil
+	}
+	for p.pos < len(p.pattern) {
+		switch char := p.pattern[p.pos]; {
+		case strings.HasPrefix(p.pattern[p.pos:], "{{"):
+			end := strings.Index(p.pattern[p.pos:], "}}")
+			if end < 0 {
+				return nil, errors.Errorf("unclosed `{{` at %v", p.pos)
+			}
+			text.WriteString(p.pattern[p.pos : p.pos+end+2])
+			p.pos += end + 2 //nolint:gomnd // The length of `}}`.
+		case char == '{':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			part, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
+		case char == '}':
+			return parts, flush()
+		case char == '#' && inPlural:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			parts = append(parts, &messageFormatPart{pound: true})
+			p.pos++
+		case char == '\'':
+			p.parseQuoted(text, inPlural)
+		default:
+			text.WriteByte(char)
+			p.pos++
+		}
+	}
+
+	return parts, flush()
+}
+
+func (p *messageFormatParser) literal(text string) (*messageFormatPart, error) {
+	part := &messageFormatPart{text: text}
+	if strings.Contains(text, "{{") {
+		tmpl, err := template.New(p.name).Parse(text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid template `%v`", text)
+		}
+		part.literal = tmpl
+	}
+
+	return part, nil
+}
+
+// parseQuoted follows the ICU apostrophe rules: a doubled apostrophe is an apostrophe and an apostrophe followed by a special character
+// starts a quoted literal, until the next apostrophe. Any other apostrophe is just an apostrophe (I.E. `You've`).
+func (p *messageFormatParser) parseQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos >= len(p.pattern) {
+		text.WriteByte('\'')
+
+		return
+	}
+	switch next := p.pattern[p.pos]; {
+	case next == '\'':
+		text.WriteByte('\'')
+		p.pos++
+
+		return
+	case next != '{' && next != '}' && (!inPlural || next != '#'):
+		text.WriteByte('\'')
+
+		return
+	}
+	for p.pos < len(p.pattern) {
+		if p.pattern[p.pos] == '\'' {
+			if p.pos+1 < len(p.pattern) && p.pattern[p.pos+1] == '\'' {
+				text.WriteByte('\'')
+				p.pos += 2 //nolint:gomnd // Both apostrophes.
+
+				continue
+			}
+			p.pos++
+
+			return
+		}
+		text.WriteByte(p.pattern[p.pos])
+		p.pos++
+	}
+}
+
+func (p *messageFormatParser) parseArgument(inPlural bool) (*messageFormatPart, error) {
+	start := p.pos
+	p.pos++
+	part := &messageFormatPart{arg: p.token()}
+	if part.arg == "" {
+		return nil, errors.Errorf("missing argument name at %v", start)
+	}
+	if p.consume('}') {
+		return part, nil
+	}
+	if !p.consume(',') {
+		return nil, errors.Errorf("expected `,` or `}` after argument `%v` at %v", part.arg, p.pos)
+	}
+	switch part.argType = p.token(); part.argType {
+	case numberArgType, dateArgType, timeArgType:
+		if p.consume(',') {
+			end := strings.IndexByte(p.pattern[p.pos:], '}')
+			if end < 0 {
+				return nil, errors.Errorf("unclosed argument `%v` at %v", part.arg, start)
+			}
+			part.style = strings.TrimSpace(p.pattern[p.pos : p.pos+end])
+			p.pos += end
+		}
+	case pluralArgType, selectOrdinalArgType, selectArgType:
+		if !p.consume(',') {
+			return nil, errors.Errorf("expected `,` after `%v` for argument `%v` at %v", part.argType, part.arg, p.pos)
+		}
+		if err := p.parseBranches(part, inPlural); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported type `%v` for argument `%v` at %v", part.argType, part.arg, start)
+	}
+	if !p.consume('}') {
+		return nil, errors.Errorf("unclosed argument `%v` at %v", part.arg, start)
+	}
+
+	return part, nil
+}
+
+func (p *messageFormatParser) parseBranches(part *messageFormatPart, inPlural bool) error {
+	part.branches = make(map[string][]*messageFormatPart)
+	for selector := p.token(); selector != ""; selector = p.token() {
+		if part.argType != selectArgType && strings.HasPrefix(selector, "offset:") {
+			offset := strings.TrimPrefix(selector, "offset:")
+			if offset == "" {
+				offset = p.token()
+			}
+			var err error
+			if part.offset, err = strconv.ParseFloat(offset, 64); err != nil {
+				return errors.Wrapf(err, "invalid offset `%v` for argument `%v`", offset, part.arg)
+			}
+
+			continue
+		}
+		if !p.consume('{') {
+			return errors.Errorf("expected `{` after `%v` for argument `%v` at %v", selector, part.arg, p.pos)
+		}
+		branch, err := p.parseMessage(inPlural || part.argType != selectArgType)
+		if err != nil {
+			return err
+		}
+		if !p.consume('}') {
+			return errors.Errorf("unclosed `%v` for argument `%v`", selector, part.arg)
+		}
+		part.branches[selector] = branch
+	}
+	if _, found := part.branches[otherSelector]; !found {
+		return errors.Errorf("missing `%v` for argument `%v`", otherSelector, part.arg)
+	}
+
+	return nil
+}
+
+func (p *messageFormatParser) token() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.pattern) && !strings.ContainsRune(" \t\r\n{},", rune(p.pattern[p.pos])) {
+		p.pos++
+	}
+	token := p.pattern[start:p.pos]
+	p.skipSpaces()
+
+	return token
+}
+
+func (p *messageFormatParser) consume(char byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.pattern) && p.pattern[p.pos] == char {
+		p.pos++
+
+		return true
+	}
+
+	return false
+}
+
+func (p *messageFormatParser) skipSpaces() {
+	for p.pos < len(p.pattern) && strings.ContainsRune(" \t\r\n", rune(p.pattern[p.pos])) {
+		p.pos++
+	}
+}
+
+// arguments returns the type (empty for simple ones) of every argument, including the fields used by the `text/template` actions.
+func (m *messageFormat) arguments() map[string]string {
+	args := make(map[string]string)
+	var collect func(parts []*messageFormatPart)
+	collect = func(parts []*messageFormatPart) {
+		for _, part := range parts {
+			if part.arg != "" {
+				args[part.arg] = part.argType
+			}
+			if part.literal != nil {
+				templateFields(part.literal.Root, args)
+			}
+			for _, branch := range part.branches {
+				collect(branch)
+			}
+		}
+	}
+	collect(m.parts)
+
+	return args
+}
+
+// templateFields collects the top level fields (I.E. `Username` for `{{.Username}}`) used by the `text/template` actions.
+func templateFields(node parse.Node, fields map[string]string) { //nolint:gocyclo,revive,cyclop // It's just a tree walk.
+	switch typedNode := node.(type) {
+	case *parse.ListNode:
+		if typedNode != nil {
+			for _, child := range typedNode.Nodes {
+				templateFields(child, fields)
+			}
+		}
+	case *parse.ActionNode:
+		templateFields(typedNode.Pipe, fields)
+	case *parse.PipeNode:
+		if typedNode != nil {
+			for _, cmd := range typedNode.Cmds {
+				for _, arg := range cmd.Args {
+					templateFields(arg, fields)
+				}
+			}
+		}
+	case *parse.FieldNode:
+		fields[typedNode.Ident[0]] = ""
+	case *parse.IfNode:
+		templateFields(typedNode.Pipe, fields)
+		templateFields(typedNode.List, fields)
+		templateFields(typedNode.ElseList, fields)
+	case *parse.RangeNode: // Their lists are relative to the element, not to the top level data.
+		templateFields(typedNode.Pipe, fields)
+		templateFields(typedNode.ElseList, fields)
+	case *parse.WithNode:
+		templateFields(typedNode.Pipe, fields)
+		templateFields(typedNode.ElseList, fields)
+	}
+}
+
+func (m *messageFormat) format(data any) (string, error) {
+	bf := new(strings.Builder)
+	err := m.formatParts(bf, m.parts, data, nil)
+
+	return bf.String(), err
+}
+
+func (m *messageFormat) formatParts(bf *strings.Builder, parts []*messageFormatPart, data any, pound *float64) error {
+	for _, part := range parts {
+		switch {
+		case part.pound && pound != nil:
+			bf.WriteString(m.formatNumber(*pound, ""))
+		case part.pound:
+			bf.WriteByte('#')
+		case part.literal != nil:
+			if err := part.literal.Execute(bf, data); err != nil {
+				return errors.Wrapf(err, "failed to execute template `%v`", part.text)
+			}
+		case part.arg == "":
+			bf.WriteString(part.text)
+		default:
+			if err := m.formatArgument(bf, part, data, pound); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *messageFormat) formatArgument(bf *strings.Builder, part *messageFormatPart, data any, pound *float64) error {
+	value, found := messageFormatArgument(data, part.arg)
+	if !found {
+		return errors.Errorf("missing argument `%v`", part.arg)
+	}
+	switch part.argType {
+	case "":
+		if nr, isNumber := toFloat(value); isNumber {
+			bf.WriteString(m.formatNumber(nr, ""))
+		} else if tm, isTime := toTime(value); isTime {
+			bf.WriteString(m.formatDate(tm, "") + " " + m.formatTime(tm))
+		} else {
+			bf.WriteString(m.sprint(value))
+		}
+	case numberArgType:
+		nr, isNumber := toFloat(value)
+		if !isNumber {
+			return errors.Errorf("argument `%v` is not a number: %#v", part.arg, value)
+		}
+		bf.WriteString(m.formatNumber(nr, part.style))
+	case dateArgType, timeArgType:
+		tm, isTime := toTime(value)
+		if !isTime {
+			return errors.Errorf("argument `%v` is not a time: %#v", part.arg, value)
+		}
+		if part.argType == dateArgType {
+			bf.WriteString(m.formatDate(tm, part.style))
+		} else {
+			bf.WriteString(m.formatTime(tm))
+		}
+	case pluralArgType, selectOrdinalArgType:
+		nr, isNumber := toFloat(value)
+		if !isNumber {
+			return errors.Errorf("argument `%v` is not a number: %#v", part.arg, value)
+		}
+		offsetNr := nr - part.offset
+
+		return m.formatParts(bf, m.pluralBranch(part, nr), data, &offsetNr)
+	case selectArgType:
+		branch, selected := part.branches[m.sprint(value)]
+		if !selected {
+			branch = part.branches[otherSelector]
+		}
+
+		return m.formatParts(bf, branch, data, pound)
+	}
+
+	return nil
+}
+
+// pluralBranch returns the exact match (I.E. `=0`), if any, or the branch of the CLDR plural form of the language, or `other`.
+func (m *messageFormat) pluralBranch(part *messageFormatPart, nr float64) []*messageFormatPart {
+	if branch, found := part.branches["="+decimal(nr)]; found {
+		return branch
+	}
+	rules := plural.Cardinal
+	if part.argType == selectOrdinalArgType {
+		rules = plural.Ordinal
+	}
+	i, v, w, f, t := pluralOperands(nr - part.offset)
+	if branch, found := part.branches[pluralFormSelectors[rules.MatchPlural(m.tag, i, v, w, f, t)]]; found {
+		return branch
+	}
+
+	return part.branches[otherSelector]
+}
+
+// pluralOperands returns the CLDR plural operands (i, v, w, f, t) of the decimal representation of the number, as formatted.
+func pluralOperands(nr float64) (i, v, w, f, t int) { //nolint:gocritic,nonamedreturns // They're the operands, as named by CLDR.
+	integer, fraction, _ := strings.Cut(decimal(math.Abs(nr)), ".")
+	trimmedFraction := strings.TrimRight(fraction, "0")
+	i, _ = strconv.Atoi(integer)         //nolint:errcheck // It's always an integer.
+	f, _ = strconv.Atoi(fraction)        //nolint:errcheck // It's either empty or an integer.
+	t, _ = strconv.Atoi(trimmedFraction) //nolint:errcheck // It's either empty or an integer.
+
+	return i, len(fraction), len(trimmedFraction), f, t
+}
+
+// decimal returns the shortest decimal representation of the number, rounded to maxFractionDigits.
+func decimal(nr float64) string {
+	rounded := strconv.FormatFloat(nr, 'f', maxFractionDigits, 64)
+	if rounded = strings.TrimRight(strings.TrimRight(rounded, "0"), "."); rounded == "-0" {
+		return "0"
+	}
+
+	return rounded
+}
+
+// formatNumber supports the `integer` and `percent` styles, besides the default one, with the grouping and decimal separators of the language.
+// Unlike ICU, the default style doesn't round to 3 fraction digits, because the mining rates can have more, but it does round to maxFractionDigits.
+func (m *messageFormat) formatNumber(nr float64, style string) string {
+	printer := message.NewPrinter(m.tag)
+	switch style {
+	case "integer":
+		return printer.Sprint(number.Decimal(nr, number.MaxFractionDigits(0)))
+	case "percent":
+		return printer.Sprint(number.Percent(nr))
+	default:
+		_, fractionDigits, _, _, _ := pluralOperands(nr)
+
+		return printer.Sprint(number.Decimal(nr, number.MaxFractionDigits(fractionDigits)))
+	}
+}
+
+// formatDate supports the `short` style (2 digit years), besides the default one.
+func (m *messageFormat) formatDate(tm stdlibtime.Time, style string) string {
+	layout := localeValue(dateLayouts, m.language, defaultDateLayout)
+	if style == "short" {
+		layout = strings.Replace(layout, "2006", "06", 1)
+	}
+
+	return tm.UTC().Format(layout)
+}
+
+func (m *messageFormat) formatTime(tm stdlibtime.Time) string {
+	return tm.UTC().Format(localeValue(timeLayouts, m.language, defaultTimeLayout))
+}
+
+func (m *messageFormat) sprint(value any) string {
+	return message.NewPrinter(m.tag).Sprint(value)
+}
+
+// localeValue returns the value of the language tag or of its closest parent (I.E. `pt-br`, then `pt`), if any, or the fallback.
+func localeValue[T any](values map[languageCode]T, lang languageCode, fallback T) T {
+	for tag := normalizeLanguageTag(lang); tag != ""; tag = parentLanguageTag(tag) {
+		if value, found := values[tag]; found {
+			return value
+		}
+	}
+
+	return fallback
+}
+
+func messageFormatArgument(data any, name string) (any, bool) {
+	if data == nil {
+		return nil, false
+	}
+	val := reflect.Indirect(reflect.ValueOf(data))
+	switch val.Kind() { //nolint:exhaustive // Only structs and maps have named arguments.
+	case reflect.Struct:
+		if field := val.FieldByName(name); field.IsValid() && field.CanInterface() {
+			return field.Interface(), true
+		}
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		if value := val.MapIndex(reflect.ValueOf(name).Convert(val.Type().Key())); value.IsValid() {
+			return value.Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+func toFloat(value any) (float64, bool) {
+	val := reflect.Indirect(reflect.ValueOf(value))
+	switch val.Kind() { //nolint:exhaustive // Only numbers.
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func toTime(value any) (stdlibtime.Time, bool) {
+	switch tm := value.(type) {
+	case stdlibtime.Time:
+		return tm, true
+	case *stdlibtime.Time:
+		if tm != nil {
+			return *tm, true
+		}
+	case *time.Time:
+		if tm != nil && tm.Time != nil {
+			return *tm.Time, true
+		}
+	case time.Time:
+		if tm.Time != nil {
+			return *tm.Time, true
+		}
+	}
+
+	return stdlibtime.Time{}, false
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"testing"
+)
+
+func TestMessageFormatFormat(t *testing.T) { //nolint:funlen // It's a table.
+	t.Parallel()
+	tests := []struct {
+		data     map[string]any
+		name     string
+		language languageCode
+		pattern  string
+		expected string
+	}{
+		{name: "plain text", language: "en", pattern: "Hello there!", expected: "Hello there!"},
+		{
+			name: "template action", language: "en", pattern: "Hi {{.Username}}, {name}!",
+			data: map[string]any{"Username": "jdoe", "name": "John"}, expected: "Hi jdoe, John!",
+		},
+		{
+			name: "plural exact match", language: "en", pattern: "{count, plural, =0 {No coins} one {# coin} other {# coins}}",
+			data: map[string]any{"count": 0}, expected: "No coins",
+		},
+		{
+			name: "plural one", language: "en", pattern: "{count, plural, =0 {No coins} one {# coin} other {# coins}}",
+			data: map[string]any{"count": 1}, expected: "1 coin",
+		},
+		{
+			name: "plural other, grouped", language: "en", pattern: "{count, plural, =0 {No coins} one {# coin} other {# coins}}",
+			data: map[string]any{"count": 1234}, expected: "1,234 coins",
+		},
+		{
+			name: "plural fraction is other", language: "en", pattern: "{count, plural, one {# coin} other {# coins}}",
+			data: map[string]any{"count": 1.5}, expected: "1.5 coins",
+		},
+		{
+			name: "plural offset", language: "en", pattern: "{count, plural, offset:1 =0 {Nobody} =1 {Only you} one {You and # other} other {You and # others}}",
+			data: map[string]any{"count": 2}, expected: "You and 1 other",
+		},
+		{
+			name: "plural few", language: "pl", pattern: "{count, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}",
+			data: map[string]any{"count": 3}, expected: "3 pliki",
+		},
+		{
+			name: "plural many", language: "pl", pattern: "{count, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}",
+			data: map[string]any{"count": 5}, expected: "5 plików",
+		},
+		{
+			name: "selectordinal", language: "en", pattern: "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+			data: map[string]any{"n": 23}, expected: "23rd",
+		},
+		{
+			name: "selectordinal teens", language: "en", pattern: "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+			data: map[string]any{"n": 11}, expected: "11th",
+		},
+		{
+			name: "select", language: "en", pattern: "{gender, select, female {She} male {He} other {They}} joined",
+			data: map[string]any{"gender": "female"}, expected: "She joined",
+		},
+		{
+			name: "select other", language: "en", pattern: "{gender, select, female {She} male {He} other {They}} joined",
+			data: map[string]any{"gender": "unknown"}, expected: "They joined",
+		},
+		{
+			name: "plural nested in select", language: "en", pattern: "{gender, select, female {She has {n, plural, one {# ref} other {# refs}}} other {They have #}}",
+			data: map[string]any{"gender": "female", "n": 2}, expected: "She has 2 refs",
+		},
+		{name: "escaped apostrophe", language: "en", pattern: "It''s {name}", data: map[string]any{"name": "you"}, expected: "It's you"},
+		{name: "lone apostrophe", language: "en", pattern: "You've won", expected: "You've won"},
+		{name: "quoted braces", language: "en", pattern: "'{name}' is literal", expected: "{name} is literal"},
+		{name: "pound outside plural", language: "en", pattern: "#1", expected: "#1"},
+		{
+			name: "quoted pound in plural", language: "en", pattern: "{n, plural, other {'#' is #}}",
+			data: map[string]any{"n": 3}, expected: "# is 3",
+		},
+		{name: "number noise", language: "en", pattern: "{n, number}", data: map[string]any{"n": 3.0000000000000004}, expected: "3"},
+		{
+			name: "plural noise", language: "en", pattern: "{n, plural, =3 {three} other {# coins}}",
+			data: map[string]any{"n": 3.0000000000000004}, expected: "three",
+		},
+		{name: "number fraction", language: "en", pattern: "{n}", data: map[string]any{"n": 0.000123}, expected: "0.000123"},
+		{name: "number separators", language: "de", pattern: "{n, number}", data: map[string]any{"n": 1234.5}, expected: "1.234,5"},
+		{name: "number integer", language: "en", pattern: "{n, number, integer}", data: map[string]any{"n": 1234.56}, expected: "1,235"},
+		{name: "number percent", language: "en", pattern: "{n, number, percent}", data: map[string]any{"n": 0.25}, expected: "25%"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			msg, err := parseMessageFormat(tt.name, tt.language, tt.pattern)
+			if err != nil {
+				t.Fatalf("parseMessageFormat(%q) failed: %v", tt.pattern, err)
+			}
+			actual, err := msg.format(tt.data)
+			if err != nil {
+				t.Fatalf("format(%q, %v) failed: %v", tt.pattern, tt.data, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("format(%q, %v) = %q, expected %q", tt.pattern, tt.data, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMessageFormatInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{name: "unexpected closing brace", pattern: "Hello}"},
+		{name: "unclosed argument", pattern: "Hello {name"},
+		{name: "missing argument name", pattern: "Hello {}"},
+		{name: "unsupported type", pattern: "{n, spellout}"},
+		{name: "missing other", pattern: "{n, plural, one {# coin}}"},
+		{name: "unclosed branch", pattern: "{n, plural, other {# coins}"},
+		{name: "invalid offset", pattern: "{n, plural, offset:x other {#}}"},
+		{name: "unclosed template action", pattern: "Hi {{.Username"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := parseMessageFormat(tt.name, "en", tt.pattern); err == nil {
+				t.Errorf("parseMessageFormat(%q) succeeded, expected an error", tt.pattern)
+			}
+		})
+	}
+}
+
+func TestMessageFormatMissingArgument(t *testing.T) {
+	t.Parallel()
+	msg, err := parseMessageFormat("missing argument", "en", "{count, plural, other {# coins}}")
+	if err != nil {
+		t.Fatalf("parseMessageFormat failed: %v", err)
+	}
+	if _, err = msg.format(map[string]any{}); err == nil {
+		t.Error("format succeeded, expected an error for the missing argument")
+	}
+}
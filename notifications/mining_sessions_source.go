@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (s *miningSessionsTableSource) Process(ctx context.Context, msg *messagebroker.Message) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline while processing message")
+	}
+	if len(msg.Value) == 0 {
+		return nil
+	}
+	type miningSession struct {
+		StartedAt *time.Time `json:"startedAt,omitempty"`
+		EndedAt   *time.Time `json:"endedAt,omitempty"`
+		UserID    string     `json:"userId,omitempty"`
+	}
+	message := new(miningSession)
+	if err := json.UnmarshalContext(ctx, msg.Value, message); err != nil {
+		return errors.Wrapf(err, "cannot unmarshal %v into %#v", string(msg.Value), message)
+	}
+	if message.UserID == "" || message.StartedAt.IsNil() || message.EndedAt.IsNil() {
+		return nil
+	}
+	// A newer session, or the current one being extended, shortened or stopped, resets the reminders. The older sessions are ignored.
+	// The messages of a user are consumed in order, so the last one about the current session is the newest.
+	sql := `INSERT INTO mining_sessions (started_at, ended_at, user_id) VALUES ($1, $2, $3)
+			ON CONFLICT(user_id) DO UPDATE
+			SET started_at = EXCLUDED.started_at,
+				ended_at = EXCLUDED.ended_at,
+				expiring_reminder_sent_at = null,
+				expired_reminder_sent_at = null,
+				reminders_deferred_until = null
+			WHERE mining_sessions.started_at < EXCLUDED.started_at
+			   OR (mining_sessions.started_at = EXCLUDED.started_at AND mining_sessions.ended_at != EXCLUDED.ended_at)`
+	if _, err := storage.Exec(ctx, s.db, sql, message.StartedAt.Time, message.EndedAt.Time, message.UserID); err != nil {
+		return errors.Wrapf(err, "failed to upsert mining session %#v", message)
+	}
+
+	return errors.Wrapf(s.recordUserActivity(ctx, message.UserID, message.StartedAt), "failed to recordUserActivity for %#v", message)
+}
+
+func (s *userTableSource) deleteMiningSession(ctx context.Context, userID string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "[deleteMiningSession] context failed")
+	}
+	sql := `DELETE FROM mining_sessions WHERE user_id = $1`
+	_, err := storage.Exec(ctx, s.db, sql, userID)
+
+	return errors.Wrapf(err, "failed to delete mining session for userID:%v", userID)
+}
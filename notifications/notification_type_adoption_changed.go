@@ -62,7 +62,7 @@ func (s *adoptionTableSource) broadcastPushNotifications(ctx context.Context, ad
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
 	now := time.Now()
-	languages := allPushNotificationTemplates[AdoptionChangedNotificationType]
+	languages := pushNotificationTemplates(AdoptionChangedNotificationType)
 	bpn := make([]*broadcastPushNotification[push.Notification[push.SubscriptionTopic]], 0, len(languages))
 	bpnDelayed := make([]*broadcastPushNotification[push.DelayedNotification], 0, len(languages))
 	data := struct{ BaseMiningRate float64 }{BaseMiningRate: adoption.BaseMiningRate}
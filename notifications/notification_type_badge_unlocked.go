@@ -94,7 +94,7 @@ func (s *achievedBadgesSource) Process(ctx context.Context, msg *messagebroker.M
 			},
 		},
 	}
-	tokens, err := s.getPushNotificationTokens(ctx, AchievementsNotificationDomain, message.UserID)
+	tokens, err := s.getPushNotificationTokens(ctx, notifType, message.UserID)
 	if err != nil || tokens == nil {
 		return multierror.Append( //nolint:wrapcheck // .
 			err,
@@ -133,7 +133,7 @@ func (s *achievedBadgesSource) Process(ctx context.Context, msg *messagebroker.M
 			}), "at least one analytics command failed to execute"),
 		).ErrorOrNil()
 	}
-	tmpl, found := allPushNotificationTemplates[notifType][tokens.Language]
+	tmpl, found := getPushNotificationTemplate(notifType, tokens.Language)
 	if !found {
 		log.Warn(fmt.Sprintf("language `%v` was not found in the `%v` push config", tokens.Language, notifType))
 
@@ -67,7 +67,7 @@ func (s *availableDailyBonusSource) Process(ctx context.Context, msg *messagebro
 			},
 		},
 	}
-	tokens, err := s.getPushNotificationTokens(ctx, DailyBonusNotificationDomain, message.UserID)
+	tokens, err := s.getPushNotificationTokens(ctx, DailyBonusNotificationType, message.UserID)
 	if err != nil || tokens == nil {
 		return multierror.Append( //nolint:wrapcheck // .
 			err,
@@ -75,7 +75,7 @@ func (s *availableDailyBonusSource) Process(ctx context.Context, msg *messagebro
 			errors.Wrapf(s.trySendEmailNotification(ctx, strconv.FormatUint(message.ExtraBonusIndex, 10), message.UserID), "failed to trySendEmailNotification for %v, message:%#v", DailyBonusNotificationType, message), //nolint:lll // .
 		).ErrorOrNil()
 	}
-	tmpl, found := allPushNotificationTemplates[DailyBonusNotificationType][tokens.Language]
+	tmpl, found := getPushNotificationTemplate(DailyBonusNotificationType, tokens.Language)
 	if !found {
 		log.Warn(fmt.Sprintf("language `%v` was not found in the `%v` push config", tokens.Language, DailyBonusNotificationType))
 
@@ -119,9 +119,9 @@ func (s *availableDailyBonusSource) trySendEmailNotification(ctx context.Context
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
-	params, err := s.getEmailNotificationParams(ctx, DailyBonusNotificationDomain, userID, true)
+	params, err := s.getEmailNotificationParams(ctx, DailyBonusNotificationType, userID, true)
 	if err != nil || params == nil || true { //nolint:revive // TODO:: temporarily disabled
-		return errors.Wrapf(err, "failed to getEmailNotificationParams for notif:%v, userID:%v", DailyBonusNotificationDomain, userID)
+		return errors.Wrapf(err, "failed to getEmailNotificationParams for notif:%v, userID:%v", DailyBonusNotificationType, userID)
 	}
 	en := &emailNotification{
 		displayName: params.DisplayName,
@@ -74,7 +74,7 @@ func (s *completedLevelsSource) Process(ctx context.Context, msg *messagebroker.
 			},
 		},
 	}
-	tokens, err := s.getPushNotificationTokens(ctx, AchievementsNotificationDomain, message.UserID)
+	tokens, err := s.getPushNotificationTokens(ctx, LevelChangedNotificationType, message.UserID)
 	if err != nil || tokens == nil {
 		return multierror.Append( //nolint:wrapcheck // .
 			err,
@@ -102,7 +102,7 @@ func (s *completedLevelsSource) Process(ctx context.Context, msg *messagebroker.
 			}), "at least one analytics command failed to execute"),
 		).ErrorOrNil()
 	}
-	tmpl, found := allPushNotificationTemplates[LevelChangedNotificationType][tokens.Language]
+	tmpl, found := getPushNotificationTemplate(LevelChangedNotificationType, tokens.Language)
 	if !found {
 		log.Warn(fmt.Sprintf("language `%v` was not found in the `%v` push config", tokens.Language, LevelChangedNotificationType))
 
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	defaultMiningSessionExpiringIn             = stdlibtime.Hour
+	defaultMiningSessionExpiredFor             = 12 * stdlibtime.Hour
+	defaultMiningSessionRemindersCheckInterval = stdlibtime.Minute
+	miningSessionRemindersBatchSize            = 1000
+)
+
+type (
+	miningSessionReminder struct {
+		EndedAt *time.Time
+		UserID  string
+		TZ      string
+	}
+)
+
+func (p *processor) startMiningSessionRemindersSender(ctx context.Context) {
+	cfg := &p.cfg.MiningSessionReminders
+	if cfg.ExpiringIn == 0 {
+		cfg.ExpiringIn = defaultMiningSessionExpiringIn
+	}
+	if cfg.ExpiredFor == 0 {
+		cfg.ExpiredFor = defaultMiningSessionExpiredFor
+	}
+	if cfg.CheckInterval == 0 {
+		cfg.CheckInterval = defaultMiningSessionRemindersCheckInterval
+	}
+	ticker := stdlibtime.NewTicker(cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.sendMiningSessionReminders(reqCtx), "failed to sendMiningSessionReminders"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *processor) sendMiningSessionReminders(ctx context.Context) error {
+	now := time.Now()
+
+	return multierror.Append( //nolint:wrapcheck // Not needed.
+		errors.Wrapf(p.sendMiningSessionRemindersOf(ctx, MiningSessionExpiringNotificationType, now),
+			"failed to send %v reminders", MiningSessionExpiringNotificationType),
+		errors.Wrapf(p.sendMiningSessionRemindersOf(ctx, MiningSessionExpiredNotificationType, now),
+			"failed to send %v reminders", MiningSessionExpiredNotificationType),
+	).ErrorOrNil()
+}
+
+func (p *processor) sendMiningSessionRemindersOf(ctx context.Context, notificationType NotificationType, now *time.Time) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sentAtColumn, from, to := "expiring_reminder_sent_at", *now.Time, now.Add(p.cfg.MiningSessionReminders.ExpiringIn)
+	if notificationType == MiningSessionExpiredNotificationType {
+		sentAtColumn, from, to = "expired_reminder_sent_at", now.Add(-p.cfg.MiningSessionReminders.ExpiredFor), *now.Time
+	}
+	sql := fmt.Sprintf(`SELECT ms.ended_at,
+							   ms.user_id,
+							   COALESCE((SELECT dm.tz
+										 FROM device_metadata dm
+										 WHERE dm.user_id = ms.user_id
+										   AND COALESCE(dm.tz, '') != ''
+										 ORDER BY dm.updated_at DESC NULLS LAST
+										 LIMIT 1), '') AS tz
+						FROM mining_sessions ms
+						WHERE ms.%[1]v IS NULL
+						  AND ms.ended_at > $1
+						  AND ms.ended_at <= $2
+						  AND (ms.reminders_deferred_until IS NULL OR ms.reminders_deferred_until <= $3)
+						ORDER BY ms.ended_at
+						LIMIT %[2]v`, sentAtColumn, miningSessionRemindersBatchSize)
+	reminders, err := storage.Select[miningSessionReminder](ctx, p.db, sql, from, to, now.Time)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select the mining sessions due for %v", notificationType)
+	}
+	var mErr *multierror.Error
+	for _, reminder := range reminders {
+		if quietTimeEnd, quiet := p.cfg.quietTimeEnd(*now.Time, reminder.TZ); quiet {
+			mErr = multierror.Append(mErr, errors.Wrapf(p.deferMiningSessionReminders(ctx, reminder, quietTimeEnd),
+				"failed to deferMiningSessionReminders for %#v", reminder))
+
+			continue
+		}
+		if claimed, cErr := p.claimMiningSessionReminder(ctx, sentAtColumn, reminder, now); cErr != nil || !claimed {
+			mErr = multierror.Append(mErr, errors.Wrapf(cErr, "failed to claimMiningSessionReminder for %#v", reminder))
+
+			continue
+		}
+		if sErr := p.sendMiningSessionReminder(ctx, notificationType, reminder); sErr != nil {
+			mErr = multierror.Append(mErr,
+				errors.Wrapf(sErr, "failed to sendMiningSessionReminder %v for %#v", notificationType, reminder),
+				errors.Wrapf(p.releaseMiningSessionReminder(ctx, sentAtColumn, reminder, now), "failed to releaseMiningSessionReminder for %#v", reminder))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
+
+// deferMiningSessionReminders stores until when, in UTC, because the time zone is discarded for TIMESTAMP columns.
+func (p *processor) deferMiningSessionReminders(ctx context.Context, reminder *miningSessionReminder, until stdlibtime.Time) error {
+	sql := `UPDATE mining_sessions SET reminders_deferred_until = $1 WHERE user_id = $2 AND ended_at = $3`
+	_, err := storage.Exec(ctx, p.db, sql, until.UTC(), reminder.UserID, reminder.EndedAt.Time)
+
+	return errors.Wrapf(err, "failed to defer the reminders of %#v until %v", reminder, until)
+}
+
+// claimMiningSessionReminder marks the reminder as sent, so that it's sent only once, even if more processors are running.
+// It's not claimed if the session was extended meanwhile.
+func (p *processor) claimMiningSessionReminder(
+	ctx context.Context, sentAtColumn string, reminder *miningSessionReminder, now *time.Time,
+) (bool, error) {
+	sql := fmt.Sprintf(`UPDATE mining_sessions SET %[1]v = $1 WHERE user_id = $2 AND ended_at = $3 AND %[1]v IS NULL`, sentAtColumn)
+	updated, err := storage.Exec(ctx, p.db, sql, now.Time, reminder.UserID, reminder.EndedAt.Time)
+
+	return updated == 1, errors.Wrapf(err, "failed to update %v for %#v", sentAtColumn, reminder)
+}
+
+// releaseMiningSessionReminder undoes claimMiningSessionReminder, if the reminder couldn't be sent, so that it's retried on the next check.
+func (p *processor) releaseMiningSessionReminder(ctx context.Context, sentAtColumn string, reminder *miningSessionReminder, claimedAt *time.Time) error {
+	sql := fmt.Sprintf(`UPDATE mining_sessions SET %[1]v = NULL WHERE user_id = $1 AND ended_at = $2 AND %[1]v = $3`, sentAtColumn)
+	_, err := storage.Exec(ctx, p.db, sql, reminder.UserID, reminder.EndedAt.Time, claimedAt.Time)
+
+	return errors.Wrapf(err, "failed to reset %v for %#v", sentAtColumn, reminder)
+}
+
+// sendMiningSessionReminder pushes the reminder, or emails it to the users that can't be reminded via push notifications.
+func (p *processor) sendMiningSessionReminder(ctx context.Context, notificationType NotificationType, reminder *miningSessionReminder) error {
+	uniqueness := strconv.FormatInt(reminder.EndedAt.UnixNano(), 10)
+	tokens, err := p.getPushNotificationTokens(ctx, notificationType, reminder.UserID)
+	if err != nil || tokens == nil {
+		return multierror.Append( //nolint:wrapcheck // .
+			err,
+			errors.Wrapf(p.sendEmailNotificationFromTemplate(ctx, notificationType, uniqueness, reminder.UserID, true),
+				"failed to sendEmailNotificationFromTemplate for %v, reminder:%#v", notificationType, reminder),
+		).ErrorOrNil()
+	}
+
+	return errors.Wrapf(p.sendPushNotificationsFromTemplate(ctx, notificationType, uniqueness, tokens),
+		"failed to sendPushNotificationsFromTemplate for %v, reminder:%#v", notificationType, reminder)
+}
@@ -69,7 +69,7 @@ func (r *repository) sendNewAgendaContactNotification(ctx context.Context, conta
 		if token.PushNotificationTokens == nil || len(*token.PushNotificationTokens) == 0 {
 			continue
 		}
-		tmpl, found := allPushNotificationTemplates[NewContactNotificationType][token.Language]
+		tmpl, found := getPushNotificationTemplate(NewContactNotificationType, token.Language)
 		if !found {
 			log.Warn(fmt.Sprintf("language `%v` was not found in the `%v` push config", token.Language, NewContactNotificationType))
 
@@ -119,15 +119,13 @@ func (r *repository) getPushNotificationTokensForNewContactNotification(
 							   u.user_id
 						FROM users u
 							 LEFT JOIN device_metadata dm
-									ON ( u.disabled_push_notification_domains IS NULL 
-										OR NOT (u.disabled_push_notification_domains && ARRAY['%[1]v', '%[2]v' ])
-								   	   )
+									ON %[1]v
 								   AND dm.user_id = u.user_id
 								   AND dm.push_notification_token IS NOT NULL 
 								   AND dm.push_notification_token != ''
 						WHERE u.user_id = $1
 							  AND $2 = ANY(u.agenda_contact_user_ids)
-						GROUP BY u.user_id`, MicroCommunityNotificationDomain, AllNotificationDomain)
+						GROUP BY u.user_id`, notificationEnabledSQL(PushNotificationChannel, NewContactNotificationType))
 
 	resp, err := storage.Select[pushNotificationTokens](ctx, r.db, sql, userID, contactID)
 	if err != nil {
@@ -62,14 +62,14 @@ func (r *repository) sendNewReferralNotification(ctx context.Context, us *users.
 			},
 		},
 	}
-	tokens, err := r.getPushNotificationTokens(ctx, MicroCommunityNotificationDomain, us.User.ReferredBy)
+	tokens, err := r.getPushNotificationTokens(ctx, NewReferralNotificationType, us.User.ReferredBy)
 	if err != nil || tokens == nil {
 		return multierror.Append( //nolint:wrapcheck // .
 			err,
 			errors.Wrapf(r.sendInAppNotification(ctx, in), "failed to sendInAppNotification for %v, notif:%#v", NewReferralNotificationType, in),
 		).ErrorOrNil()
 	}
-	tmpl, found := allPushNotificationTemplates[NewReferralNotificationType][tokens.Language]
+	tmpl, found := getPushNotificationTemplate(NewReferralNotificationType, tokens.Language)
 	if !found {
 		log.Warn(fmt.Sprintf("language `%v` was not found in the `%v` push config", tokens.Language, NewReferralNotificationType))
 
@@ -11,7 +11,9 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/users"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/notifications/inapp"
 	"github.com/ice-blockchain/wintr/notifications/push"
 	"github.com/ice-blockchain/wintr/time"
@@ -20,10 +22,12 @@ import (
 type (
 	news struct {
 		*NotificationChannels
-		ID       string `json:"id,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
-		Language string `json:"language,omitempty" example:"en"`
-		ImageURL string `json:"imageUrl,omitempty" example:"https://somewebsite.com/blockchain.jpg"`
-		URL      string `json:"url,omitempty" example:"https://somewebsite.com/blockchain"`
+		Targeting *Targeting `json:"targeting,omitempty"`
+		Draft     *bool      `json:"draft,omitempty"`
+		ID        string     `json:"id,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Language  string     `json:"language,omitempty" example:"en"`
+		ImageURL  string     `json:"imageUrl,omitempty" example:"https://somewebsite.com/blockchain.jpg"`
+		URL       string     `json:"url,omitempty" example:"https://somewebsite.com/blockchain"`
 	}
 )
 
@@ -39,6 +43,7 @@ func (s *newsTableSource) Process(ctx context.Context, msg *messagebroker.Messag
 		return errors.Wrapf(err, "cannot unmarshal %v into %#v", string(msg.Value), message)
 	}
 	if message.ID == "" ||
+		(message.Draft != nil && *message.Draft) ||
 		message.NotificationChannels == nil ||
 		message.NotificationChannels.NotificationChannels == nil ||
 		len(*message.NotificationChannels.NotificationChannels) == 0 {
@@ -49,7 +54,9 @@ func (s *newsTableSource) Process(ctx context.Context, msg *messagebroker.Messag
 		notificationChannels[channel] = true
 	}
 	errs := make([]error, 0, 1+1+1)
-	if notificationChannels[PushNotificationChannel] || notificationChannels[PushOrFallbackToEmailNotificationChannel] {
+	if (notificationChannels[PushNotificationChannel] || notificationChannels[PushOrFallbackToEmailNotificationChannel]) && !message.Targeting.IsEmpty() {
+		errs = append(errs, errors.Wrapf(s.sendTargetedPushNotifications(ctx, message), "failed to sendTargetedPushNotifications for news:%#v", message))
+	} else if notificationChannels[PushNotificationChannel] || notificationChannels[PushOrFallbackToEmailNotificationChannel] {
 		errs = append(errs, errors.Wrapf(s.broadcastPushNotifications(ctx, message), "failed to broadcastPushNotifications for news:%#v", message))
 	}
 	if notificationChannels[InAppNotificationChannel] || notificationChannels[PushNotificationChannel] || notificationChannels[PushOrFallbackToEmailNotificationChannel] { //nolint:lll // .
@@ -67,7 +74,7 @@ func (s *newsTableSource) broadcastPushNotifications(ctx context.Context, newsAr
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
-	tmpl, found := allPushNotificationTemplates[NewsAddedNotificationType][newsArticle.Language]
+	tmpl, found := getPushNotificationTemplate(NewsAddedNotificationType, newsArticle.Language)
 	if !found {
 		return errors.Errorf("language `%v` was not found in the `%v` push config", newsArticle.Language, NewsAddedNotificationType)
 	}
@@ -100,6 +107,142 @@ func (s *newsTableSource) broadcastPushNotifications(ctx context.Context, newsAr
 	).ErrorOrNil(), "failed to broadcastPushNotification(%v) %#v", NewsAddedNotificationType, bpn)
 }
 
+// Topics can't be targeted, so we have to go through every user of that language and check each of their devices individually.
+//
+//nolint:funlen // .
+func (s *newsTableSource) sendTargetedPushNotifications(ctx context.Context, newsArticle *news) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	tmpl, found := getPushNotificationTemplate(NewsAddedNotificationType, newsArticle.Language)
+	if !found {
+		return errors.Errorf("language `%v` was not found in the `%v` push config", newsArticle.Language, NewsAddedNotificationType)
+	}
+	// The announcement is recorded once, like the broadcasts are, while the devices that were already sent it are skipped via SENT_NOTIFICATIONS.
+	sa := &sentAnnouncement{
+		SentAt:   time.Now(),
+		Language: newsArticle.Language,
+		sentAnnouncementPK: sentAnnouncementPK{
+			Uniqueness:               newsArticle.ID,
+			NotificationType:         NewsAddedNotificationType,
+			NotificationChannel:      PushNotificationChannel,
+			NotificationChannelValue: "targeted",
+		},
+	}
+	if err := s.insertSentAnnouncement(ctx, sa); err != nil && !storage.IsErr(err, storage.ErrDuplicate) {
+		return errors.Wrapf(err, "failed to insert %#v", sa)
+	}
+	const batchSize = 1000
+	var lastUserID, lastDeviceUniqueID string
+	for {
+		devices, err := s.getTargetedDevices(ctx, newsArticle, lastUserID, lastDeviceUniqueID, batchSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to getTargetedDevices for news:%#v, after (%v,%v)", newsArticle, lastUserID, lastDeviceUniqueID)
+		}
+		if len(devices) == 0 {
+			return nil
+		}
+		now := time.Now()
+		pn := make([]*pushNotification, 0, len(devices))
+		for _, device := range devices {
+			if !newsArticle.Targeting.Matches(device.audience()) {
+				continue
+			}
+			pn = append(pn, &pushNotification{
+				pn: &push.Notification[push.DeviceToken]{
+					Data:     s.pushNotificationData(newsArticle),
+					Target:   device.PushNotificationToken,
+					Title:    tmpl.getTitle(nil),
+					Body:     tmpl.getBody(nil),
+					ImageURL: newsArticle.ImageURL,
+				},
+				sn: &sentNotification{
+					SentAt:   now,
+					Language: newsArticle.Language,
+					sentNotificationPK: sentNotificationPK{
+						UserID:                   device.UserID,
+						Uniqueness:               newsArticle.ID,
+						NotificationType:         NewsAddedNotificationType,
+						NotificationChannel:      PushNotificationChannel,
+						NotificationChannelValue: string(device.PushNotificationToken),
+					},
+				},
+			})
+		}
+		if err = runConcurrently(ctx, s.sendPushNotificationOnce, pn); err != nil {
+			return errors.Wrapf(err, "failed to sendPushNotifications atleast to some devices for %v, args:%#v", NewsAddedNotificationType, pn)
+		}
+		if len(devices) < batchSize {
+			return nil
+		}
+		lastUserID, lastDeviceUniqueID = devices[len(devices)-1].UserID, devices[len(devices)-1].DeviceUniqueID
+	}
+}
+
+type (
+	targetedDevice struct {
+		PushNotificationToken push.DeviceToken
+		UserID                string
+		DeviceUniqueID        string
+		Country               string
+		AppVersion            string
+		NewUser               bool
+		HasReferrals          bool
+	}
+)
+
+func (s *newsTableSource) getTargetedDevices(
+	ctx context.Context, newsArticle *news, afterUserID, afterDeviceUniqueID string, limit uint64,
+) ([]*targetedDevice, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := fmt.Sprintf(`SELECT dm.push_notification_token,
+							   u.user_id,
+							   dm.device_unique_id,
+							   u.country,
+							   COALESCE(dm.app_version, '') AS app_version,
+							   (u.created_at IS NOT NULL AND u.created_at > $4) AS new_user,
+							   EXISTS(SELECT 1 FROM users r WHERE r.referred_by = u.user_id AND r.user_id != u.user_id) AS has_referrals
+						FROM users u
+							 JOIN device_metadata dm
+							   ON dm.user_id = u.user_id
+							  AND dm.push_notification_token IS NOT NULL
+							  AND dm.push_notification_token != ''
+						WHERE u.language = $1
+						  AND %[1]v
+						  AND (u.user_id, dm.device_unique_id) > ($2, $3)
+						  AND NOT EXISTS(SELECT 1
+										 FROM sent_notifications sn
+										 WHERE sn.user_id = u.user_id
+										   AND sn.uniqueness = $5
+										   AND sn.notification_type = '%[2]v'
+										   AND sn.notification_channel = '%[3]v'
+										   AND sn.notification_channel_value = dm.push_notification_token)
+						ORDER BY u.user_id, dm.device_unique_id
+						LIMIT $6`, notificationEnabledSQL(PushNotificationChannel, NewsAddedNotificationType), NewsAddedNotificationType, PushNotificationChannel)
+	args := []any{newsArticle.Language, afterUserID, afterDeviceUniqueID, s.newUserAudienceThreshold().Time, newsArticle.ID, int64(limit)}
+	resp, err := storage.Select[targetedDevice](ctx, s.db, sql, args...)
+
+	return resp, errors.Wrapf(err, "failed to select targeted devices for args:%#v", args...)
+}
+
+func (d *targetedDevice) audience() *Audience {
+	aud := &Audience{
+		Segments:   make(users.Enum[AudienceSegment], 0, len(AllAudienceSegments)),
+		Country:    d.Country,
+		AppVersion: NormalizeAppVersion(d.AppVersion),
+	}
+	if d.HasReferrals {
+		aud.Segments = append(aud.Segments, HasReferralsAudienceSegment)
+	}
+	if d.NewUser {
+		aud.Segments = append(aud.Segments, NewUserAudienceSegment)
+	}
+
+	return aud
+}
+
 func (s *newsTableSource) broadcastInAppNotifications(ctx context.Context, newsArticle *news) error { //nolint:funlen // .
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
@@ -144,18 +287,19 @@ func (s *newsTableSource) broadcastEmailNotifications(ctx context.Context, fallb
 
 func (s *newsTableSource) pushNotificationData(newsArticle *news) map[string]string {
 	return map[string]string{
-		"deeplink": s.deeplink(newsArticle),
+		"deeplink": s.deeplink(newsArticle, PushNotificationChannel),
 	}
 }
 
 func (s *newsTableSource) inAppNotificationData(newsArticle *news) map[string]any {
 	return map[string]any{
-		"deeplink": s.deeplink(newsArticle),
+		"deeplink": s.deeplink(newsArticle, InAppNotificationChannel),
 		"imageUrl": newsArticle.ImageURL,
 	}
 }
 
-func (s *newsTableSource) deeplink(newsArticle *news) string {
-	return fmt.Sprintf("%v://browser?contentType=news&contentId=%v&contentLanguage=%v&url=%v",
-		s.cfg.DeeplinkScheme, newsArticle.ID, newsArticle.Language, url.QueryEscape(newsArticle.URL))
+// The contentSource is meant to be sent back when the news article is marked as viewed, so that views can be attributed to the channel.
+func (s *newsTableSource) deeplink(newsArticle *news, source NotificationChannel) string {
+	return fmt.Sprintf("%v://browser?contentType=news&contentId=%v&contentLanguage=%v&contentSource=%v&url=%v",
+		s.cfg.DeeplinkScheme, newsArticle.ID, newsArticle.Language, source, url.QueryEscape(newsArticle.URL))
 }
@@ -37,6 +37,11 @@ func (s *userPingSource) Process(ctx context.Context, msg *messagebroker.Message
 		return errors.Wrapf(err, "failed to getUserByID for pingedBy:%v", pingedBy)
 	}
 	now := time.Now()
+	if testNotificationRunFrom(ctx) == nil {
+		if err = s.recordUserActivity(ctx, message.PingedBy, now); err != nil {
+			return errors.Wrapf(err, "failed to recordUserActivity for pingedBy:%v", message.PingedBy)
+		}
+	}
 	uniqueness := strconv.FormatInt(message.LastPingCooldownEndedAt.UnixNano()/s.cfg.PingCooldown.Nanoseconds(), 10)
 	deeplink := fmt.Sprintf("%v://home", s.cfg.DeeplinkScheme)
 	imageURL := s.pictureClient.DownloadURL(fmt.Sprintf("profile/%v", pingedBy.ProfilePictureName))
@@ -69,14 +74,14 @@ func (s *userPingSource) Process(ctx context.Context, msg *messagebroker.Message
 			},
 		},
 	}
-	tokens, err := s.getPushNotificationTokens(ctx, MicroCommunityNotificationDomain, message.UserID)
+	tokens, err := s.getPushNotificationTokens(ctx, PingNotificationType, message.UserID)
 	if err != nil || tokens == nil {
 		return multierror.Append( //nolint:wrapcheck // .
 			err,
 			errors.Wrapf(s.sendInAppNotification(ctx, in), "failed to sendInAppNotification for %v, notif:%#v", PingNotificationType, in),
 		).ErrorOrNil()
 	}
-	tmpl, found := allPushNotificationTemplates[PingNotificationType][tokens.Language]
+	tmpl, found := getPushNotificationTemplate(PingNotificationType, tokens.Language)
 	if !found {
 		log.Warn(fmt.Sprintf("language `%v` was not found in the `%v` push config", tokens.Language, PingNotificationType))
 
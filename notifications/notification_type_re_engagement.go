@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	defaultReEngagementCheckInterval = stdlibtime.Minute
+	reEngagementBatchSize            = 1000
+)
+
+type (
+	inactiveUser struct {
+		LastActiveAt          *time.Time
+		UserID                string
+		TZ                    string
+		ReEngagementStepsSent uint8
+	}
+)
+
+func (p *processor) startReEngagementSender(ctx context.Context) {
+	cfg := &p.cfg.ReEngagement
+	if len(cfg.Steps) == 0 {
+		return
+	}
+	if len(cfg.Steps) > len(ReEngagementNotificationTypes) {
+		log.Panic(errors.Errorf("at most %v re-engagement steps are supported", len(ReEngagementNotificationTypes)))
+	}
+	for ix, step := range cfg.Steps {
+		if step.Channel != PushNotificationChannel && step.Channel != EmailNotificationChannel {
+			log.Panic(errors.Errorf("invalid channel `%v` for re-engagement step %v", step.Channel, ix+1))
+		}
+		if ix > 0 && step.InactiveFor <= cfg.Steps[ix-1].InactiveFor {
+			log.Panic(errors.Errorf("re-engagement step %v has to be after step %v", ix+1, ix))
+		}
+	}
+	if cfg.CheckInterval == 0 {
+		cfg.CheckInterval = defaultReEngagementCheckInterval
+	}
+	ticker := stdlibtime.NewTicker(cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.sendReEngagementSteps(reqCtx), "failed to sendReEngagementSteps"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *processor) sendReEngagementSteps(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	now := time.Now()
+	steps := p.cfg.ReEngagement.Steps
+	// The users inactive for long enough for the step after the ones they were already sent.
+	inactiveSince := make([]string, 0, len(steps))
+	args := make([]any, 0, len(steps)+1)
+	args = append(args, now.Time)
+	for ix, step := range steps {
+		args = append(args, now.Add(-step.InactiveFor))
+		inactiveSince = append(inactiveSince, fmt.Sprintf("WHEN %v THEN $%v::TIMESTAMP", ix, len(args)))
+	}
+	sql := fmt.Sprintf(`SELECT ua.last_active_at,
+							   ua.user_id,
+							   COALESCE((SELECT dm.tz
+										 FROM device_metadata dm
+										 WHERE dm.user_id = ua.user_id
+										   AND COALESCE(dm.tz, '') != ''
+										 ORDER BY dm.updated_at DESC NULLS LAST
+										 LIMIT 1), '') AS tz,
+							   ua.re_engagement_steps_sent
+						FROM user_activity ua
+						WHERE ua.re_engagement_steps_sent < %[1]v
+						  AND ua.last_active_at <= (CASE ua.re_engagement_steps_sent %[2]v END)
+						  AND (ua.re_engagement_deferred_until IS NULL OR ua.re_engagement_deferred_until <= $1)
+						ORDER BY ua.last_active_at
+						LIMIT %[3]v`, len(steps), strings.Join(inactiveSince, " "), reEngagementBatchSize)
+	inactiveUsers, err := storage.Select[inactiveUser](ctx, p.db, sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to select the users due for re-engagement steps")
+	}
+	var mErr *multierror.Error
+	for _, usr := range inactiveUsers {
+		if quietTimeEnd, quiet := p.cfg.quietTimeEnd(*now.Time, usr.TZ); quiet {
+			mErr = multierror.Append(mErr, errors.Wrapf(p.deferReEngagementSteps(ctx, usr, quietTimeEnd),
+				"failed to deferReEngagementSteps for %#v", usr))
+
+			continue
+		}
+		step := p.dueReEngagementStep(usr, now)
+		if claimed, cErr := p.claimReEngagementStep(ctx, usr, step); cErr != nil || !claimed {
+			mErr = multierror.Append(mErr, errors.Wrapf(cErr, "failed to claimReEngagementStep %v for %#v", step+1, usr))
+
+			continue
+		}
+		if sErr := p.sendReEngagementStep(ctx, usr, step); sErr != nil {
+			mErr = multierror.Append(mErr,
+				errors.Wrapf(sErr, "failed to sendReEngagementStep %v for %#v", step+1, usr),
+				errors.Wrapf(p.releaseReEngagementStep(ctx, usr, step), "failed to releaseReEngagementStep %v for %#v", step+1, usr))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
+
+// dueReEngagementStep is the last step the user is inactive for long enough for, so that the overdue ones are skipped.
+func (p *processor) dueReEngagementStep(usr *inactiveUser, now *time.Time) int {
+	step := int(usr.ReEngagementStepsSent)
+	inactiveFor := now.Sub(*usr.LastActiveAt.Time)
+	for step+1 < len(p.cfg.ReEngagement.Steps) && p.cfg.ReEngagement.Steps[step+1].InactiveFor <= inactiveFor {
+		step++
+	}
+
+	return step
+}
+
+// deferReEngagementSteps stores until when, in UTC, because the time zone is discarded for TIMESTAMP columns.
+func (p *processor) deferReEngagementSteps(ctx context.Context, usr *inactiveUser, until stdlibtime.Time) error {
+	sql := `UPDATE user_activity SET re_engagement_deferred_until = $1 WHERE user_id = $2 AND last_active_at = $3`
+	_, err := storage.Exec(ctx, p.db, sql, until.UTC(), usr.UserID, usr.LastActiveAt.Time)
+
+	return errors.Wrapf(err, "failed to defer the re-engagement steps of %#v until %v", usr, until)
+}
+
+// claimReEngagementStep marks the step as sent, so that it's sent only once, even if more processors are running.
+// It's not claimed if the user was active meanwhile.
+func (p *processor) claimReEngagementStep(ctx context.Context, usr *inactiveUser, step int) (bool, error) {
+	sql := `UPDATE user_activity
+			SET re_engagement_steps_sent = $1
+			WHERE user_id = $2
+			  AND last_active_at = $3
+			  AND re_engagement_steps_sent = $4`
+	updated, err := storage.Exec(ctx, p.db, sql, step+1, usr.UserID, usr.LastActiveAt.Time, usr.ReEngagementStepsSent)
+
+	return updated == 1, errors.Wrapf(err, "failed to update re_engagement_steps_sent for %#v", usr)
+}
+
+// releaseReEngagementStep undoes claimReEngagementStep, if the step couldn't be sent, so that it's retried on the next check.
+func (p *processor) releaseReEngagementStep(ctx context.Context, usr *inactiveUser, step int) error {
+	sql := `UPDATE user_activity
+			SET re_engagement_steps_sent = $1
+			WHERE user_id = $2
+			  AND last_active_at = $3
+			  AND re_engagement_steps_sent = $4`
+	_, err := storage.Exec(ctx, p.db, sql, usr.ReEngagementStepsSent, usr.UserID, usr.LastActiveAt.Time, step+1)
+
+	return errors.Wrapf(err, "failed to reset re_engagement_steps_sent for %#v", usr)
+}
+
+func (p *processor) sendReEngagementStep(ctx context.Context, usr *inactiveUser, step int) error {
+	notificationType := ReEngagementNotificationTypes[step]
+	uniqueness := strconv.FormatInt(usr.LastActiveAt.UnixNano(), 10)
+	if p.cfg.ReEngagement.Steps[step].Channel == EmailNotificationChannel {
+		return errors.Wrapf(p.sendEmailNotificationFromTemplate(ctx, notificationType, uniqueness, usr.UserID, false),
+			"failed to sendEmailNotificationFromTemplate for %v, user:%#v", notificationType, usr)
+	}
+	tokens, err := p.getPushNotificationTokens(ctx, notificationType, usr.UserID)
+	if err != nil || tokens == nil {
+		return errors.Wrapf(err, "failed to getPushNotificationTokens for %v, userID:%v", notificationType, usr.UserID)
+	}
+
+	return errors.Wrapf(p.sendPushNotificationsFromTemplate(ctx, notificationType, uniqueness, tokens),
+		"failed to sendPushNotificationsFromTemplate for %v, user:%#v", notificationType, usr)
+}
@@ -72,7 +72,7 @@ func (s *enabledRolesSource) Process(ctx context.Context, msg *messagebroker.Mes
 			},
 		},
 	}
-	tokens, err := s.getPushNotificationTokens(ctx, AchievementsNotificationDomain, message.UserID)
+	tokens, err := s.getPushNotificationTokens(ctx, RoleChangedNotificationType, message.UserID)
 	if err != nil || tokens == nil {
 		return multierror.Append( //nolint:wrapcheck // .
 			err,
@@ -100,7 +100,7 @@ func (s *enabledRolesSource) Process(ctx context.Context, msg *messagebroker.Mes
 			}), "at least one analytics command failed to execute"),
 		).ErrorOrNil()
 	}
-	tmpl, found := allPushNotificationTemplates[RoleChangedNotificationType][tokens.Language]
+	tmpl, found := getPushNotificationTemplate(RoleChangedNotificationType, tokens.Language)
 	if !found {
 		log.Warn(fmt.Sprintf("language `%v` was not found in the `%v` push config", tokens.Language, RoleChangedNotificationType))
 
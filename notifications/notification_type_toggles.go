@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) ToggleNotificationChannelNotificationType(
+	ctx context.Context, channel NotificationChannel, notificationType NotificationType, enabled *bool, userID string,
+) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	if enabled == nil {
+		sql := `DELETE FROM notification_type_toggles WHERE user_id = $1 AND notification_channel = $2 AND notification_type = $3`
+		_, err := storage.Exec(ctx, r.db, sql, userID, channel, notificationType)
+
+		return errors.Wrapf(err, "failed to delete notification_type_toggles for userID:%v,channel:%v,type:%v", userID, channel, notificationType)
+	}
+	sql := `INSERT INTO notification_type_toggles (UPDATED_AT, USER_ID, NOTIFICATION_CHANNEL, NOTIFICATION_TYPE, ENABLED) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT(USER_ID, NOTIFICATION_CHANNEL, NOTIFICATION_TYPE) DO UPDATE
+			SET ENABLED = EXCLUDED.ENABLED,
+				UPDATED_AT = EXCLUDED.UPDATED_AT`
+	params := []any{time.Now().Time, userID, channel, notificationType, *enabled}
+	if _, err := storage.Exec(ctx, r.db, sql, params...); err != nil {
+		return errors.Wrapf(err, "failed to upsert notification_type_toggles for params:%#v", params...)
+	}
+
+	return nil
+}
+
+func getNotificationTypeToggles(
+	ctx context.Context, db storage.Querier, channel NotificationChannel, userID string,
+) (map[NotificationType]bool, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	type notificationTypeToggle struct {
+		NotificationType NotificationType
+		Enabled          bool
+	}
+	sql := `SELECT notification_type, enabled FROM notification_type_toggles WHERE user_id = $1 AND notification_channel = $2`
+	resp, err := storage.Select[notificationTypeToggle](ctx, db, sql, userID, channel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select notification_type_toggles for userID:%v,channel:%v", userID, channel)
+	}
+	toggles := make(map[NotificationType]bool, len(resp))
+	for _, toggle := range resp {
+		toggles[toggle.NotificationType] = toggle.Enabled
+	}
+
+	return toggles, nil
+}
+
+// applyNotificationTypeToggles fills in the notification types of each domain toggle, using the overrides, if any.
+func applyNotificationTypeToggles(domainToggles []*NotificationChannelToggle, overrides map[NotificationType]bool) {
+	for _, domainToggle := range domainToggles {
+		for _, notificationType := range AllNotificationTypes {
+			if NotificationTypeDomains[notificationType] != domainToggle.Type {
+				continue
+			}
+			toggle := &NotificationTypeToggle{Type: notificationType, Enabled: domainToggle.Enabled}
+			if enabled, overridden := overrides[notificationType]; overridden {
+				toggle.Enabled, toggle.Overridden = enabled, true
+			}
+			domainToggle.NotificationTypes = append(domainToggle.NotificationTypes, toggle)
+		}
+	}
+}
+
+// notificationEnabledSQL is the SQL condition for a notification type to be enabled, on a channel, for the user aliased as `u`:
+// `disable_all` takes precedence, then the notification type toggle, if any, then the domain toggle, if any.
+func notificationEnabledSQL(channel NotificationChannel, notificationType NotificationType) string {
+	return fmt.Sprintf(`(NOT EXISTS(SELECT 1
+								FROM notification_domain_toggles ndt
+								WHERE ndt.user_id = u.user_id
+								  AND ndt.notification_channel = '%[1]v'
+								  AND ndt.notification_domain = '%[2]v'
+								  AND ndt.enabled)
+						 AND COALESCE((SELECT ntt.enabled
+									   FROM notification_type_toggles ntt
+									   WHERE ntt.user_id = u.user_id
+										 AND ntt.notification_channel = '%[1]v'
+										 AND ntt.notification_type = '%[4]v'),
+									  (SELECT ndt.enabled
+									   FROM notification_domain_toggles ndt
+									   WHERE ndt.user_id = u.user_id
+										 AND ndt.notification_channel = '%[1]v'
+										 AND ndt.notification_domain = '%[3]v'),
+									  TRUE))`,
+		channel, DisableAllNotificationDomain, NotificationTypeDomains[notificationType], notificationType)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/husky/fake"
 	appcfg "github.com/ice-blockchain/wintr/config"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
@@ -48,17 +49,25 @@ func StartProcessor(ctx context.Context, cancel context.CancelFunc) Processor {
 
 	var mbConsumer messagebroker.Client
 	prc := &processor{repository: &repository{
-		cfg:                     &cfg,
-		db:                      storage.MustConnect(context.Background(), ddl, applicationYamlKey), //nolint:contextcheck // We need to gracefully shut it down.
-		mb:                      messagebroker.MustConnect(ctx, applicationYamlKey),
-		pushNotificationsClient: push.New(applicationYamlKey),
-		pictureClient:           picture.New(applicationYamlKey),
-		emailClient:             email.New(applicationYamlKey),
+		cfg:           &cfg,
+		db:            storage.MustConnect(context.Background(), ddl, applicationYamlKey), //nolint:contextcheck // We need to gracefully shut it down.
+		mb:            messagebroker.MustConnect(ctx, applicationYamlKey),
+		pictureClient: picture.New(applicationYamlKey),
 		/*
 			personalInAppFeed:       inapp.New(applicationYamlKey, "notifications"),
 			globalInAppFeed:         inapp.New(applicationYamlKey, "announcements"),
 		*/
 	}}
+	// Only the providers that are used are built, so that capture mode needs no credentials and nothing is left unclosed.
+	switch {
+	case cfg.CaptureMode.Enabled:
+		prc.useCapturingSinks()
+	case cfg.FakeProviders:
+		prc.pushNotificationsClient, prc.emailClient = fake.NewPushClient(applicationYamlKey), fake.NewEmailClient(applicationYamlKey)
+	default:
+		prc.pushNotificationsClient, prc.emailClient = push.New(applicationYamlKey), email.New(applicationYamlKey)
+	}
+	log.Error(errors.Wrap(prc.reloadPushNotificationTemplates(ctx), "failed to load the published push notification templates"))
 	//nolint:contextcheck // It's intended. Cuz we want to close everything gracefully.
 	mbConsumer = messagebroker.MustConnectAndStartConsuming(context.Background(), cancel, applicationYamlKey,
 		&userTableSource{processor: prc},
@@ -72,10 +81,21 @@ func StartProcessor(ctx context.Context, cancel context.CancelFunc) Processor {
 		&completedLevelsSource{processor: prc},
 		&enabledRolesSource{processor: prc},
 		&agendaContactsSource{processor: prc},
+		&miningSessionsTableSource{processor: prc},
+		&viewedNewsSource{processor: prc},
 	)
 	prc.shutdown = closeAll(mbConsumer, prc.mb, prc.db, prc.pushNotificationsClient.Close)
+	go func() {
+		log.Error(errors.Wrap(prc.backfillUserActivity(ctx), "failed to backfillUserActivity"))
+	}()
 	go prc.startOldSentNotificationsCleaner(ctx)
 	go prc.startOldSentAnnouncementsCleaner(ctx)
+	go prc.startPushNotificationTemplatesReloader(ctx)
+	go prc.startMiningSessionRemindersSender(ctx)
+	go prc.startReEngagementSender(ctx)
+	if cfg.CaptureMode.Enabled {
+		go prc.startOldCapturedNotificationsCleaner(ctx)
+	}
 
 	return prc
 }
@@ -321,6 +341,29 @@ func (r *repository) deleteSentAnnouncement(ctx context.Context, sa *sentAnnounc
 	return errors.Wrapf(err, "failed to insert sent announcement %#v", sa)
 }
 
+// GetAnnouncementStats is limited by how long sent announcements are kept.
+func (r *repository) GetAnnouncementStats(
+	ctx context.Context, notificationType NotificationType, uniqueness string, from, to *time.Time,
+) ([]*AnnouncementStats, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `SELECT date_trunc('day', sent_at) AS day,
+				   language,
+				   notification_channel,
+				   COUNT(1) AS sent
+			FROM sent_announcements
+			WHERE uniqueness = $1
+			  AND notification_type = $2
+			  AND sent_at >= $3
+			  AND sent_at < $4
+			GROUP BY 1, 2, 3
+			ORDER BY 1, 2, 3`
+	result, err := storage.Select[AnnouncementStats](ctx, r.db, sql, uniqueness, notificationType, from.Time, to.Time)
+
+	return result, errors.Wrapf(err, "failed to select announcement stats for (%v,%v)", notificationType, uniqueness)
+}
+
 func (cfg *config) IsLevelNotificationDisabled(levelName string) bool {
 	if len(cfg.DisabledAchievementsNotifications.Levels) == 0 {
 		return false
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"strings"
+)
+
+// getPushNotificationTemplate returns the template, of the notification type, in the closest language available, for a BCP-47 language tag.
+// In every language, the published template, if any, takes precedence over the embedded one.
+func getPushNotificationTemplate(notificationType NotificationType, language string) (*pushNotificationTemplate, bool) {
+	templates, published := allPushNotificationTemplates[notificationType], publishedPushNotificationTemplatesOf(notificationType)
+	for _, lang := range languageFallbackChain(language) {
+		if tmpl, found := published[lang]; found {
+			return tmpl, true
+		}
+		if tmpl, found := templates[lang]; found {
+			return tmpl, true
+		}
+	}
+
+	return nil, false
+}
+
+// languageFallbackChain returns, from the most to the least specific, the language codes to look for a template with,
+// always ending with the default language. I.E. for `pt-BR`: `pt-br`, `pt`, `en`.
+func languageFallbackChain(language string) []languageCode {
+	tag := normalizeLanguageTag(language)
+	chain := make([]languageCode, 0, strings.Count(tag, "-")+3) //nolint:gomnd // The default language and aliases.
+	for tag != "" {
+		chain = append(chain, tag)
+		if alias, found := languageAliases[tag]; found {
+			chain = append(chain, alias)
+		}
+		tag = parentLanguageTag(tag)
+	}
+	if len(chain) == 0 || chain[len(chain)-1] != defaultLanguage {
+		chain = append(chain, defaultLanguage)
+	}
+
+	return chain
+}
+
+// normalizeLanguageTag lowercases the tag and uses `-` as the subtag separator, because some clients send `pt_BR`.
+func normalizeLanguageTag(language string) languageCode {
+	return strings.Trim(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(language)), "_", "-"), "-")
+}
+
+// parentLanguageTag removes the last subtag, I.E. `zh-hant-tw` becomes `zh-hant`, or returns an empty string if there's none left.
+func parentLanguageTag(tag languageCode) languageCode {
+	if ix := strings.LastIndex(tag, "-"); ix >= 0 {
+		return tag[:ix]
+	}
+
+	return ""
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"sort"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	defaultPushNotificationTemplatesReloadInterval = stdlibtime.Minute
+	// The number the numeric placeholders are previewed with, if no data is provided. It's rendered with the `other` plural branch, in most languages.
+	samplePreviewNumber             = 21
+	pushNotificationTemplateColumns = `t.created_at,
+									   t.version,
+									   t.notification_type,
+									   t.language,
+									   t.created_by,
+									   t.title,
+									   t.body`
+)
+
+func (r *repository) GetPushNotificationTemplates(ctx context.Context, notificationType NotificationType) ([]*PushNotificationTemplate, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `SELECT ` + pushNotificationTemplateColumns + `,
+				   p.published_at,
+				   true AS published
+			FROM published_push_notification_templates p
+				 JOIN push_notification_templates t
+				   ON t.notification_type = p.notification_type
+				  AND t.language = p.language
+				  AND t.version = p.version
+			WHERE p.notification_type = $1`
+	published, err := storage.Select[PushNotificationTemplate](ctx, r.db, sql, notificationType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select published push notification templates for %v", notificationType)
+	}
+	resp := make([]*PushNotificationTemplate, 0, len(allPushNotificationTemplates[notificationType])+len(published))
+	publishedLanguages := make(map[languageCode]struct{}, len(published))
+	for _, tmpl := range published {
+		publishedLanguages[tmpl.Language] = struct{}{}
+		resp = append(resp, tmpl)
+	}
+	for language := range allPushNotificationTemplates[notificationType] {
+		if _, found := publishedLanguages[language]; !found {
+			tmpl := embeddedPushNotificationTemplate(notificationType, language)
+			tmpl.Published = true
+			resp = append(resp, tmpl)
+		}
+	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Language < resp[j].Language })
+
+	return resp, nil
+}
+
+func (r *repository) GetPushNotificationTemplateVersions(
+	ctx context.Context, notificationType NotificationType, language string,
+) ([]*PushNotificationTemplate, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	language = normalizeLanguageTag(language)
+	sql := `SELECT ` + pushNotificationTemplateColumns + `,
+				   p.published_at,
+				   p.version IS NOT NULL AS published
+			FROM push_notification_templates t
+				 LEFT JOIN published_push_notification_templates p
+						ON p.notification_type = t.notification_type
+					   AND p.language = t.language
+					   AND p.version = t.version
+			WHERE t.notification_type = $1
+			  AND t.language = $2
+			ORDER BY t.version DESC`
+	resp, err := storage.Select[PushNotificationTemplate](ctx, r.db, sql, notificationType, language)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select push notification templates for %v/%v", notificationType, language)
+	}
+	if _, found := allPushNotificationTemplates[notificationType][language]; found {
+		tmpl := embeddedPushNotificationTemplate(notificationType, language)
+		tmpl.Published = true
+		for _, version := range resp {
+			tmpl.Published = tmpl.Published && !version.Published
+		}
+		resp = append(resp, tmpl)
+	}
+	if len(resp) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "no push notification templates for %v/%v", notificationType, language)
+	}
+
+	return resp, nil
+}
+
+func (r *repository) PreviewPushNotificationTemplate(
+	ctx context.Context, notificationType NotificationType, language string, version uint64, data map[string]any,
+) (*PushNotificationTemplatePreview, error) {
+	tmpl, err := r.getPushNotificationTemplateVersion(ctx, notificationType, normalizeLanguageTag(language), version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get push notification template %v/%v, version %v", notificationType, language, version)
+	}
+	parsed, err := newPushNotificationTemplate(tmpl.NotificationType, tmpl.Language, tmpl.Title, tmpl.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %#v", tmpl)
+	}
+	if data == nil {
+		data = sampleTranslationData(parsed.title.arguments(), parsed.body.arguments(), samplePreviewNumber)
+	}
+	preview := &PushNotificationTemplatePreview{Data: data}
+	if preview.Title, err = parsed.title.format(data); err != nil {
+		return nil, errors.Wrapf(ErrInvalidPushNotificationTemplate, "failed to render the title for %#v: %v", data, err)
+	}
+	if preview.Body, err = parsed.body.format(data); err != nil {
+		return nil, errors.Wrapf(ErrInvalidPushNotificationTemplate, "failed to render the body for %#v: %v", data, err)
+	}
+
+	return preview, nil
+}
+
+func (r *repository) getPushNotificationTemplateVersion(
+	ctx context.Context, notificationType NotificationType, language languageCode, version uint64,
+) (*PushNotificationTemplate, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	if version == 0 {
+		if _, found := allPushNotificationTemplates[notificationType][language]; !found {
+			return nil, errors.Wrapf(ErrNotFound, "no embedded push notification template for %v/%v", notificationType, language)
+		}
+
+		return embeddedPushNotificationTemplate(notificationType, language), nil
+	}
+	sql := `SELECT ` + pushNotificationTemplateColumns + `
+			FROM push_notification_templates t
+			WHERE t.notification_type = $1
+			  AND t.language = $2
+			  AND t.version = $3`
+	resp, err := storage.Get[PushNotificationTemplate](ctx, r.db, sql, notificationType, language, version)
+
+	return resp, errors.Wrapf(err, "failed to get push notification template %v/%v, version %v", notificationType, language, version)
+}
+
+func (r *repository) CreatePushNotificationTemplate(ctx context.Context, tmpl *PushNotificationTemplate, userID string) (*PushNotificationTemplate, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	language := normalizeLanguageTag(tmpl.Language)
+	if err := validatePushNotificationTemplate(tmpl.NotificationType, language, tmpl.Title, tmpl.Body); err != nil {
+		return nil, errors.Wrapf(err, "invalid %#v", tmpl)
+	}
+	// Concurrent versions of the same template end up with the same version, so all but one fail with ErrDuplicate.
+	sql := `INSERT INTO push_notification_templates (CREATED_AT, VERSION, NOTIFICATION_TYPE, LANGUAGE, CREATED_BY, TITLE, BODY)
+				SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5, $6
+				FROM push_notification_templates
+				WHERE notification_type = $2
+				  AND language = $3
+			RETURNING *`
+	resp, err := storage.ExecOne[PushNotificationTemplate](ctx, r.db, sql, time.Now().Time, tmpl.NotificationType, language, userID, tmpl.Title, tmpl.Body)
+
+	return resp, errors.Wrapf(err, "failed to insert push notification template %#v, userID:%v", tmpl, userID)
+}
+
+func (r *repository) PublishPushNotificationTemplate(
+	ctx context.Context, notificationType NotificationType, language string, version uint64, userID string,
+) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	language = normalizeLanguageTag(language)
+	var err error
+	if version == 0 {
+		if _, found := allPushNotificationTemplates[notificationType][language]; !found {
+			return errors.Wrapf(ErrNotFound, "no embedded push notification template for %v/%v", notificationType, language)
+		}
+		sql := `DELETE FROM published_push_notification_templates WHERE notification_type = $1 AND language = $2`
+		_, err = storage.Exec(ctx, r.db, sql, notificationType, language)
+	} else {
+		sql := `INSERT INTO published_push_notification_templates (PUBLISHED_AT, VERSION, NOTIFICATION_TYPE, LANGUAGE, PUBLISHED_BY)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT(NOTIFICATION_TYPE, LANGUAGE) DO UPDATE
+				SET VERSION = EXCLUDED.VERSION,
+					PUBLISHED_AT = EXCLUDED.PUBLISHED_AT,
+					PUBLISHED_BY = EXCLUDED.PUBLISHED_BY`
+		_, err = storage.Exec(ctx, r.db, sql, time.Now().Time, version, notificationType, language, userID)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish push notification template %v/%v, version %v, userID:%v", notificationType, language, version, userID)
+	}
+	// The other instances pick it up on their next reload.
+	log.Error(errors.Wrap(r.reloadPushNotificationTemplates(ctx), "failed to reloadPushNotificationTemplates"))
+
+	return nil
+}
+
+// validatePushNotificationTemplate checks that it can be rendered with what the English template the services are built with is rendered with.
+func validatePushNotificationTemplate(notificationType NotificationType, language languageCode, title, body string) error {
+	reference := allPushNotificationTemplates[notificationType][defaultLanguage]
+	if reference == nil || language == "" {
+		return errors.Wrapf(ErrInvalidPushNotificationTemplate, "unknown notification type `%v` or language `%v`", notificationType, language)
+	}
+	tmpl, err := newPushNotificationTemplate(notificationType, language, title, body)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidPushNotificationTemplate, "%v", err)
+	}
+	var errs []error
+	for _, field := range []*translationLintField{
+		{name: "title", reference: reference.title, translation: tmpl.title, raw: tmpl.Title},
+		{name: "body", reference: reference.body, translation: tmpl.body, raw: tmpl.Body},
+	} {
+		for _, problem := range field.lint(notificationType, language) {
+			if problem.Severity == ErrorTranslationProblemSeverity {
+				errs = append(errs, errors.Wrapf(ErrInvalidPushNotificationTemplate, "%v %v", problem.Field, problem.Message))
+			}
+		}
+	}
+
+	return multierror.Append(nil, errs...).ErrorOrNil() //nolint:wrapcheck // They're wrapped already.
+}
+
+func embeddedPushNotificationTemplate(notificationType NotificationType, language languageCode) *PushNotificationTemplate {
+	tmpl := allPushNotificationTemplates[notificationType][language]
+
+	return &PushNotificationTemplate{
+		NotificationType: notificationType,
+		Language:         language,
+		Title:            tmpl.Title,
+		Body:             tmpl.Body,
+	}
+}
+
+// pushNotificationTemplates returns the templates of the notification type, by language, the published ones taking precedence over the embedded ones.
+func pushNotificationTemplates(notificationType NotificationType) map[languageCode]*pushNotificationTemplate {
+	published := publishedPushNotificationTemplatesOf(notificationType)
+	if len(published) == 0 {
+		return allPushNotificationTemplates[notificationType]
+	}
+	templates := make(map[languageCode]*pushNotificationTemplate, len(allPushNotificationTemplates[notificationType])+len(published))
+	for language, tmpl := range allPushNotificationTemplates[notificationType] {
+		templates[language] = tmpl
+	}
+	for language, tmpl := range published {
+		templates[language] = tmpl
+	}
+
+	return templates
+}
+
+func publishedPushNotificationTemplatesOf(notificationType NotificationType) map[languageCode]*pushNotificationTemplate {
+	if published := publishedPushNotificationTemplates.Load(); published != nil {
+		return (*published)[notificationType]
+	}
+
+	return nil
+}
+
+func (r *repository) reloadPushNotificationTemplates(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `SELECT ` + pushNotificationTemplateColumns + `
+			FROM published_push_notification_templates p
+				 JOIN push_notification_templates t
+				   ON t.notification_type = p.notification_type
+				  AND t.language = p.language
+				  AND t.version = p.version`
+	resp, err := storage.Select[PushNotificationTemplate](ctx, r.db, sql)
+	if err != nil {
+		return errors.Wrap(err, "failed to select published push notification templates")
+	}
+	published := make(map[NotificationType]map[languageCode]*pushNotificationTemplate, len(AllNotificationTypes))
+	for _, tmpl := range resp {
+		parsed, pErr := newPushNotificationTemplate(tmpl.NotificationType, tmpl.Language, tmpl.Title, tmpl.Body)
+		if pErr != nil {
+			log.Error(errors.Wrapf(pErr, "ignoring the published push notification template %#v", tmpl))
+
+			continue
+		}
+		if published[tmpl.NotificationType] == nil {
+			published[tmpl.NotificationType] = make(map[languageCode]*pushNotificationTemplate)
+		}
+		published[tmpl.NotificationType][tmpl.Language] = parsed
+	}
+	publishedPushNotificationTemplates.Store(&published)
+
+	return nil
+}
+
+func (p *processor) startPushNotificationTemplatesReloader(ctx context.Context) {
+	interval := p.cfg.PushNotificationTemplatesReloadInterval
+	if interval == 0 {
+		interval = defaultPushNotificationTemplatesReloadInterval
+	}
+	ticker := stdlibtime.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 30 * stdlibtime.Second
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.reloadPushNotificationTemplates(reqCtx), "failed to reloadPushNotificationTemplates"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -3,11 +3,9 @@
 package notifications
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"strings"
-	"text/template"
 
 	"github.com/goccy/go-json"
 	"github.com/hashicorp/go-multierror"
@@ -22,30 +20,24 @@ import (
 
 type (
 	pushNotificationTemplate struct {
-		title, body *template.Template
+		title, body *messageFormat
 		Title       string `json:"title"` //nolint:revive // That's intended.
 		Body        string `json:"body"`  //nolint:revive // That's intended.
 	}
 )
 
 func (t *pushNotificationTemplate) getTitle(data any) string {
-	if data == nil {
-		return t.Title
-	}
-	bf := new(bytes.Buffer)
-	log.Panic(errors.Wrapf(t.title.Execute(bf, data), "failed to execute title template for data:%#v", data))
+	title, err := t.title.format(data)
+	log.Panic(errors.Wrapf(err, "failed to format title template for data:%#v", data))
 
-	return bf.String()
+	return title
 }
 
 func (t *pushNotificationTemplate) getBody(data any) string {
-	if data == nil {
-		return t.Body
-	}
-	bf := new(bytes.Buffer)
-	log.Panic(errors.Wrapf(t.body.Execute(bf, data), "failed to execute body template for data:%#v", data))
+	body, err := t.body.format(data)
+	log.Panic(errors.Wrapf(err, "failed to format body template for data:%#v", data))
 
-	return bf.String()
+	return body
 }
 
 func loadPushNotificationTranslationTemplates() {
@@ -62,19 +54,38 @@ func loadPushNotificationTranslationTemplates() {
 			if fErr != nil {
 				panic(fErr)
 			}
-			var tmpl pushNotificationTemplate
-			err = json.Unmarshal(content, &tmpl)
-			if err != nil {
-				panic(err)
+			language := normalizeLanguageTag(strings.Split(file.Name(), ".")[0])
+			tmpl, pErr := parsePushNotificationTemplate(notificationType, language, content)
+			if pErr != nil {
+				panic(pErr)
 			}
-			language := strings.Split(file.Name(), ".")[0]
-			tmpl.title = template.Must(template.New(fmt.Sprintf("push_%v_%v_title", notificationType, language)).Parse(tmpl.Title))
-			tmpl.body = template.Must(template.New(fmt.Sprintf("push_%v_%v_body", notificationType, language)).Parse(tmpl.Body))
-			allPushNotificationTemplates[notificationType][language] = &tmpl
+			allPushNotificationTemplates[notificationType][language] = tmpl
 		}
 	}
 }
 
+func parsePushNotificationTemplate(notificationType NotificationType, language languageCode, content []byte) (*pushNotificationTemplate, error) {
+	var tmpl pushNotificationTemplate
+	if err := json.Unmarshal(content, &tmpl); err != nil {
+		return nil, errors.Wrapf(err, "invalid json for push %v/%v", notificationType, language)
+	}
+
+	return newPushNotificationTemplate(notificationType, language, tmpl.Title, tmpl.Body)
+}
+
+func newPushNotificationTemplate(notificationType NotificationType, language languageCode, title, body string) (*pushNotificationTemplate, error) {
+	tmpl := pushNotificationTemplate{Title: title, Body: body}
+	var err error
+	if tmpl.title, err = parseMessageFormat(fmt.Sprintf("push_%v_%v_title", notificationType, language), language, tmpl.Title); err != nil {
+		return nil, errors.Wrapf(err, "invalid title for push %v/%v", notificationType, language)
+	}
+	if tmpl.body, err = parseMessageFormat(fmt.Sprintf("push_%v_%v_body", notificationType, language), language, tmpl.Body); err != nil {
+		return nil, errors.Wrapf(err, "invalid body for push %v/%v", notificationType, language)
+	}
+
+	return &tmpl, nil
+}
+
 type (
 	pushNotificationTokens struct {
 		PushNotificationTokens *users.Enum[push.DeviceToken]
@@ -83,7 +94,7 @@ type (
 )
 
 func (r *repository) getPushNotificationTokens(
-	ctx context.Context, domain NotificationDomain, userID string,
+	ctx context.Context, notificationType NotificationType, userID string,
 ) (*pushNotificationTokens, error) {
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
@@ -93,17 +104,15 @@ func (r *repository) getPushNotificationTokens(
 							   u.user_id
 						FROM users u
 							 LEFT JOIN device_metadata dm
-									ON ( u.disabled_push_notification_domains IS NULL 
-										OR NOT (u.disabled_push_notification_domains && ARRAY['%[1]v','%[2]v'] )
-								   	   )
+									ON %[1]v
 								   AND dm.user_id = u.user_id
 								   AND dm.push_notification_token IS NOT NULL 
 								   AND dm.push_notification_token != ''
 						WHERE u.user_id = $1
-						GROUP BY u.user_id`, domain, AllNotificationDomain)
+						GROUP BY u.user_id`, notificationEnabledSQL(PushNotificationChannel, notificationType))
 	resp, err := storage.Get[pushNotificationTokens](ctx, r.db, sql, userID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to select for push notification tokens for `%v`, userID:%#v", domain, userID)
+		return nil, errors.Wrapf(err, "failed to select for push notification tokens for `%v`, userID:%#v", notificationType, userID)
 	}
 	if resp.PushNotificationTokens == nil || len(*resp.PushNotificationTokens) == 0 {
 		return nil, nil //nolint:nilnil // .
@@ -135,22 +144,30 @@ func (r *repository) sendPushNotification(ctx context.Context, pn *pushNotificat
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
-	if err := r.insertSentNotification(ctx, pn.sn); err != nil {
+	run := testNotificationRunFrom(ctx)
+	if run != nil {
+		if run.recordPush(pn.pn); !run.Sent {
+			return nil
+		}
+	} else if err := r.insertSentNotification(ctx, pn.sn); err != nil {
 		return errors.Wrapf(err, "failed to insert %#v", pn.sn)
 	}
 	responder := make(chan error, 1)
 	defer close(responder)
 	r.pushNotificationsClient.Send(ctx, pn.pn, responder)
 	if err := <-responder; err != nil {
-		var cErr error
+		var cErr, rollbackErr error
 		if errors.Is(err, push.ErrInvalidDeviceToken) {
 			cErr = r.clearInvalidPushNotificationToken(ctx, pn.sn.UserID, pn.pn.Target)
 		}
+		if run == nil {
+			rollbackErr = r.deleteSentNotification(ctx, pn.sn)
+		}
 
 		return multierror.Append( //nolint:wrapcheck // Not needed.
 			errors.Wrapf(cErr, "failed to clearInvalidPushNotificationToken for userID:%#v, push token:%#v", pn.sn.UserID, pn.pn.Target),
 			errors.Wrapf(err, "failed to send push notification:%#v, desired to be sent:%#v", pn.pn, pn.sn),
-			errors.Wrapf(r.deleteSentNotification(ctx, pn.sn), "failed to delete SENT_NOTIFICATIONS as a rollback for %#v", pn.sn),
+			errors.Wrapf(rollbackErr, "failed to delete SENT_NOTIFICATIONS as a rollback for %#v", pn.sn),
 		).ErrorOrNil()
 	}
 
@@ -247,3 +264,48 @@ func (r *repository) broadcastPushNotificationDelayed(ctx context.Context, bpn *
 
 	return nil
 }
+
+// sendPushNotificationsFromTemplate pushes the template of the notification type, with no data, to all the devices of the user.
+// The devices that were already sent it, I.E. before a retry, are skipped.
+func (r *repository) sendPushNotificationsFromTemplate(
+	ctx context.Context, notificationType NotificationType, uniqueness string, tokens *pushNotificationTokens,
+) error {
+	tmpl, found := getPushNotificationTemplate(notificationType, tokens.Language)
+	if !found {
+		return errors.Errorf("language `%v` was not found in the `%v` push config", tokens.Language, notificationType)
+	}
+	now := time.Now()
+	deeplink := fmt.Sprintf("%v://home", r.cfg.DeeplinkScheme)
+	pn := make([]*pushNotification, 0, len(*tokens.PushNotificationTokens))
+	for _, token := range *tokens.PushNotificationTokens {
+		pn = append(pn, &pushNotification{
+			pn: &push.Notification[push.DeviceToken]{
+				Data:   map[string]string{"deeplink": deeplink},
+				Target: token,
+				Title:  tmpl.getTitle(nil),
+				Body:   tmpl.getBody(nil),
+			},
+			sn: &sentNotification{
+				SentAt:   now,
+				Language: tokens.Language,
+				sentNotificationPK: sentNotificationPK{
+					UserID:                   tokens.UserID,
+					Uniqueness:               uniqueness,
+					NotificationType:         notificationType,
+					NotificationChannel:      PushNotificationChannel,
+					NotificationChannelValue: string(token),
+				},
+			},
+		})
+	}
+
+	return errors.Wrapf(runConcurrently(ctx, r.sendPushNotificationOnce, pn), "failed to sendPushNotifications atleast to some devices for %v, args:%#v", notificationType, pn) //nolint:lll // .
+}
+
+func (r *repository) sendPushNotificationOnce(ctx context.Context, pn *pushNotification) error {
+	if err := r.sendPushNotification(ctx, pn); err != nil && !storage.IsErr(err, storage.ErrDuplicate) {
+		return err
+	}
+
+	return nil
+}
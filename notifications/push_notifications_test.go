@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"net"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/google/uuid"
+
+	"github.com/ice-blockchain/husky/fake"
+	appcfg "github.com/ice-blockchain/wintr/config"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/notifications/push"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// testDBAddress is the address of the db of the test environment (see `make start-test-environment` and .testdata/application.yaml).
+const testDBAddress = "localhost:5432"
+
+func newTestRepository(tb testing.TB) *repository {
+	tb.Helper()
+	conn, err := net.DialTimeout("tcp", testDBAddress, stdlibtime.Second)
+	if err != nil {
+		tb.Skipf("the test environment is not running: %v", err)
+	}
+	_ = conn.Close() //nolint:errcheck // It's just a probe.
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	if !cfg.FakeProviders {
+		tb.Fatal("fakeProviders has to be enabled for the tests")
+	}
+	db := storage.MustConnect(context.Background(), ddl, applicationYamlKey)
+	tb.Cleanup(func() { _ = db.Close() }) //nolint:errcheck // It's just a test.
+
+	return &repository{cfg: &cfg, db: db, pushNotificationsClient: fake.NewPushClient(applicationYamlKey)}
+}
+
+//nolint:funlen // It's a test.
+func TestSendPushNotificationToInvalidDeviceToken(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepository(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*stdlibtime.Second)
+	defer cancel()
+	userID, token := uuid.NewString(), push.DeviceToken(uuid.NewString())
+	_, err := storage.Exec(ctx, repo.db, `INSERT INTO users (user_id, created_at) VALUES ($1, $2)`, userID, time.Now().Time)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = storage.Exec(context.Background(), repo.db, `DELETE FROM device_metadata WHERE user_id = $1`, userID) //nolint:errcheck // It's just a test.
+		_, _ = storage.Exec(context.Background(), repo.db, `DELETE FROM users WHERE user_id = $1`, userID)           //nolint:errcheck // It's just a test.
+	})
+	_, err = storage.Exec(ctx, repo.db, `INSERT INTO device_metadata (user_id, device_unique_id, push_notification_token) VALUES ($1, $2, $3)`,
+		userID, uuid.NewString(), token)
+	if err != nil {
+		t.Fatalf("failed to insert device metadata: %v", err)
+	}
+	pushClient := fake.NewPushClient(applicationYamlKey)
+	pushClient.InjectFailure(string(token), push.ErrInvalidDeviceToken)
+	t.Cleanup(func() { pushClient.InjectFailure(string(token), nil) })
+	pn := &pushNotification{
+		pn: &push.Notification[push.DeviceToken]{Target: token, Title: "title", Body: "body"},
+		sn: &sentNotification{
+			SentAt:   time.Now(),
+			Language: "en",
+			sentNotificationPK: sentNotificationPK{
+				UserID:                   userID,
+				Uniqueness:               uuid.NewString(),
+				NotificationType:         NewsAddedNotificationType,
+				NotificationChannel:      PushNotificationChannel,
+				NotificationChannelValue: string(token),
+			},
+		},
+	}
+
+	if err = repo.sendPushNotification(ctx, pn); err == nil {
+		t.Fatal("sendPushNotification succeeded, expected it to fail with push.ErrInvalidDeviceToken")
+	}
+	type count struct {
+		Count int64
+	}
+	remainingTokens, err := storage.Get[count](ctx, repo.db,
+		`SELECT count(1) AS count FROM device_metadata WHERE user_id = $1 AND push_notification_token IS NOT NULL`, userID)
+	if err != nil {
+		t.Fatalf("failed to count push notification tokens: %v", err)
+	}
+	if remainingTokens.Count != 0 {
+		t.Errorf("the invalid push notification token was not cleared")
+	}
+	sent, err := storage.Get[count](ctx, repo.db, `SELECT count(1) AS count FROM sent_notifications WHERE user_id = $1`, userID)
+	if err != nil {
+		t.Fatalf("failed to count sent notifications: %v", err)
+	}
+	if sent.Count != 0 {
+		t.Errorf("the sent notification was not rolled back")
+	}
+	for _, sentPN := range pushClient.Sent() {
+		if sentPN.Target == token {
+			t.Errorf("the push notification was recorded as sent to the invalid token")
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	stdlibtime "time"
+)
+
+// quietTimeEnd returns when the quiet time ends, if it's quiet time for a user, in the time zone of the user, at that moment.
+func (c *config) quietTimeEnd(now stdlibtime.Time, tz string) (end stdlibtime.Time, quiet bool) {
+	start, stop := c.QuietTime.Start, c.QuietTime.End
+	if start == stop {
+		return now, false
+	}
+	local := now.In(timeZone(tz))
+	midnight := stdlibtime.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	sinceMidnight := local.Sub(midnight)
+	switch {
+	case start < stop && sinceMidnight >= start && sinceMidnight < stop:
+		return midnight.Add(stop), true
+	case start > stop && sinceMidnight >= start:
+		return midnight.AddDate(0, 0, 1).Add(stop), true
+	case start > stop && sinceMidnight < stop:
+		return midnight.Add(stop), true
+	default:
+		return now, false
+	}
+}
+
+// timeZone parses the time zone the devices report, I.E. `+03:00`. It falls back to UTC.
+func timeZone(tz string) *stdlibtime.Location {
+	if tz == "" {
+		return stdlibtime.UTC
+	}
+	if offset, err := stdlibtime.Parse("-07:00", tz); err == nil {
+		_, seconds := offset.Zone()
+
+		return stdlibtime.FixedZone(tz, seconds)
+	}
+	if location, err := stdlibtime.LoadLocation(tz); err == nil {
+		return location
+	}
+
+	return stdlibtime.UTC
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/email"
+	"github.com/ice-blockchain/wintr/notifications/inapp"
+	"github.com/ice-blockchain/wintr/notifications/push"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+type (
+	// | testNotificationRun is carried by the context of a SendTestNotification call, so that what's sent is recorded and not deduplicated.
+	testNotificationRun struct {
+		*TestNotification
+		mx sync.Mutex
+	}
+)
+
+//nolint:gochecknoglobals // It's a static mapping.
+var (
+	testNotificationBadgeGroupTypes = map[NotificationType]string{
+		LevelBadgeUnlockedNotificationType:  "level",
+		CoinBadgeUnlockedNotificationType:   "coin",
+		SocialBadgeUnlockedNotificationType: "social",
+	}
+	// The source panics for anything else.
+	testNotificationBadgeTypeRegex = regexp.MustCompile(`^[a-z]\d+$`)
+)
+
+func (p *processor) SendTestNotification(
+	ctx context.Context, notificationType NotificationType, userID string, data map[string]any, dryRun bool,
+) (*TestNotification, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	if data == nil {
+		data = make(map[string]any, 1)
+	}
+	run := &testNotificationRun{TestNotification: &TestNotification{Sent: !dryRun}}
+	ctx = context.WithValue(ctx, testNotificationCtxValueKey, run) //nolint:revive,staticcheck // Not an issue.
+	var err error
+	switch notificationType { //nolint:exhaustive // The broadcasted ones are not supported.
+	case NewReferralNotificationType:
+		// The data is the snapshot of the referral, that joined the team of the user.
+		data["referredBy"] = userID
+		snapshot := new(users.UserSnapshot)
+		if err = remarshalTestNotificationData(data, snapshot); err == nil {
+			err = p.sendNewReferralNotification(ctx, snapshot)
+		}
+	case NewContactNotificationType:
+		data["userId"] = userID
+		contact := new(users.Contact)
+		if err = remarshalTestNotificationData(data, contact); err == nil {
+			err = p.sendNewAgendaContactNotification(ctx, contact)
+		}
+	case DailyBonusNotificationType, PingNotificationType, RoleChangedNotificationType, LevelChangedNotificationType,
+		LevelBadgeUnlockedNotificationType, CoinBadgeUnlockedNotificationType, SocialBadgeUnlockedNotificationType:
+		err = p.processTestNotification(ctx, notificationType, userID, data)
+	default:
+		return nil, errors.Wrapf(ErrTestNotificationNotSupported, "notificationType:%v", notificationType)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to send test notification %v for userID:%v, data:%#v", notificationType, userID, data)
+	}
+
+	return run.TestNotification, nil
+}
+
+func (p *processor) processTestNotification(ctx context.Context, notificationType NotificationType, userID string, data map[string]any) error {
+	var source messagebroker.Processor
+	data["userId"] = userID
+	switch notificationType { //nolint:exhaustive // Only the ones with a message of their own.
+	case DailyBonusNotificationType:
+		source = &availableDailyBonusSource{processor: p}
+	case PingNotificationType:
+		if _, found := data["lastPingCooldownEndedAt"]; !found {
+			data["lastPingCooldownEndedAt"] = time.Now()
+		}
+		source = &userPingSource{processor: p}
+	case RoleChangedNotificationType:
+		source = &enabledRolesSource{processor: p}
+	case LevelChangedNotificationType:
+		source = &completedLevelsSource{processor: p}
+	default:
+		if badgeType, _ := data["type"].(string); !testNotificationBadgeTypeRegex.MatchString(badgeType) { //nolint:errcheck // Not needed.
+			return errors.Wrapf(ErrTestNotificationNotSupported, "invalid badge type `%v`", data["type"])
+		}
+		data["groupType"] = testNotificationBadgeGroupTypes[notificationType]
+		source = &achievedBadgesSource{processor: p}
+	}
+	value, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", data)
+	}
+
+	return errors.Wrapf(source.Process(ctx, &messagebroker.Message{Key: userID, Value: value}), "failed to process %v", string(value))
+}
+
+func remarshalTestNotificationData(data map[string]any, dst any) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", data)
+	}
+
+	return errors.Wrapf(json.Unmarshal(value, dst), "failed to unmarshal %v", string(value))
+}
+
+// testNotificationRunFrom returns the SendTestNotification call the context is of, if any.
+func testNotificationRunFrom(ctx context.Context) *testNotificationRun {
+	run, _ := ctx.Value(testNotificationCtxValueKey).(*testNotificationRun) //nolint:errcheck // Not needed.
+
+	return run
+}
+
+func (run *testNotificationRun) recordPush(pn *push.Notification[push.DeviceToken]) {
+	run.mx.Lock()
+	defer run.mx.Unlock()
+	run.Push = append(run.Push, pn)
+}
+
+func (run *testNotificationRun) recordInApp(in *inapp.Parcel) {
+	run.mx.Lock()
+	defer run.mx.Unlock()
+	run.InApp = append(run.InApp, in)
+}
+
+func (run *testNotificationRun) recordEmail(en *email.Parcel, to string) {
+	run.mx.Lock()
+	defer run.mx.Unlock()
+	te := &TestEmail{To: to, Subject: en.Subject}
+	if en.Body != nil {
+		te.Body = en.Body.Data
+	}
+	run.Email = append(run.Email, te)
+}
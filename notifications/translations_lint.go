@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	stdlibtime "time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+type (
+	translationLintField struct {
+		reference, translation *messageFormat
+		name, raw              string
+		maxLength              int
+	}
+)
+
+//nolint:gochecknoglobals // They're static lists.
+var (
+	sampleTranslationArguments = map[string]any{
+		"Username":       "@jdoe",
+		"BadgeName":      "Ice Breaker",
+		"BaseMiningRate": 16.0, //nolint:gomnd // It's just a sample.
+	}
+	// So that the `=0`, `one`, `two`, `few` and `many` branches get rendered too, in most languages.
+	sampleTranslationNumbers = []float64{0, 1, 2, 3, 5, 21, 1234.5}
+)
+
+// EmbeddedTranslations returns the translations the services are built with, rooted at the `translations` directory.
+func EmbeddedTranslations() fs.FS {
+	sub, err := fs.Sub(translations, "translations")
+	log.Panic(errors.Wrap(err, "failed to open the embedded translations"))
+
+	return sub
+}
+
+// LintTranslations validates every push notification template, in the `push/<notificationType>/<language>.txt` files of fsys.
+// The English templates are the reference: every other language has to use the same placeholders, and the ones that are missing fall back to English.
+func LintTranslations(fsys fs.FS, opts *TranslationLintOptions) []*TranslationProblem {
+	problems := make([]*TranslationProblem, 0, len(AllNotificationTypes))
+	templates := make(map[NotificationType]map[languageCode]*pushNotificationTemplate, len(AllNotificationTypes))
+	allLanguages := make(map[languageCode]struct{})
+	for _, notificationType := range AllNotificationTypes {
+		var readProblems []*TranslationProblem
+		templates[notificationType], readProblems = readPushNotificationTemplates(fsys, notificationType)
+		problems = append(problems, readProblems...)
+		for language := range templates[notificationType] {
+			allLanguages[language] = struct{}{}
+		}
+	}
+	languages := make([]languageCode, 0, len(allLanguages))
+	for language := range allLanguages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, notificationType := range AllNotificationTypes {
+		problems = append(problems, lintPushNotificationTemplates(notificationType, templates[notificationType], languages, opts)...)
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].NotificationType != problems[j].NotificationType {
+			return problems[i].NotificationType < problems[j].NotificationType
+		}
+
+		return problems[i].Language < problems[j].Language
+	})
+
+	return problems
+}
+
+func readPushNotificationTemplates(
+	fsys fs.FS, notificationType NotificationType,
+) (map[languageCode]*pushNotificationTemplate, []*TranslationProblem) {
+	files, err := fs.ReadDir(fsys, fmt.Sprintf("push/%v", notificationType))
+	if err != nil {
+		return nil, []*TranslationProblem{translationError(notificationType, "", "", "failed to read the templates: %v", err)}
+	}
+	templates := make(map[languageCode]*pushNotificationTemplate, len(files))
+	var problems []*TranslationProblem
+	for _, file := range files {
+		language := normalizeLanguageTag(strings.Split(file.Name(), ".")[0])
+		content, fErr := fs.ReadFile(fsys, fmt.Sprintf("push/%v/%v", notificationType, file.Name()))
+		if fErr != nil {
+			problems = append(problems, translationError(notificationType, language, "", "failed to read %v: %v", file.Name(), fErr))
+
+			continue
+		}
+		tmpl, pErr := parsePushNotificationTemplate(notificationType, language, content)
+		if pErr != nil {
+			problems = append(problems, translationError(notificationType, language, "", "%v", pErr))
+		}
+		templates[language] = tmpl // It's nil if it's broken, so that it's not reported as missing too.
+	}
+
+	return templates, problems
+}
+
+func lintPushNotificationTemplates(
+	notificationType NotificationType, templates map[languageCode]*pushNotificationTemplate, languages []languageCode, opts *TranslationLintOptions,
+) []*TranslationProblem {
+	reference, found := templates[defaultLanguage]
+	if reference == nil {
+		if found {
+			return nil
+		}
+
+		return []*TranslationProblem{translationError(notificationType, defaultLanguage, "", "missing, but every other language falls back to it")}
+	}
+	var problems []*TranslationProblem
+	for _, language := range languages {
+		tmpl, hasFile := templates[language]
+		if !hasFile {
+			problems = append(problems, &TranslationProblem{
+				NotificationType: notificationType,
+				Language:         language,
+				Message:          fmt.Sprintf("missing, so it falls back to %v", defaultLanguage),
+				Severity:         WarningTranslationProblemSeverity,
+			})
+
+			continue
+		}
+		if tmpl == nil {
+			continue
+		}
+		for _, field := range []*translationLintField{
+			{name: "title", reference: reference.title, translation: tmpl.title, raw: tmpl.Title, maxLength: opts.MaxTitleLength},
+			{name: "body", reference: reference.body, translation: tmpl.body, raw: tmpl.Body, maxLength: opts.MaxBodyLength},
+		} {
+			problems = append(problems, field.lint(notificationType, language)...)
+		}
+	}
+
+	return problems
+}
+
+func (f *translationLintField) lint(notificationType NotificationType, language languageCode) []*TranslationProblem {
+	var problems []*TranslationProblem
+	if strings.TrimSpace(f.raw) == "" {
+		return append(problems, translationError(notificationType, language, f.name, "empty"))
+	}
+	expected, actual := f.reference.arguments(), f.translation.arguments()
+	if missing := missingArguments(expected, actual); len(missing) != 0 {
+		problems = append(problems, translationError(notificationType, language, f.name, "missing the placeholders %v, that %v has", missing, defaultLanguage))
+	}
+	if unknown := missingArguments(actual, expected); len(unknown) != 0 {
+		problems = append(problems, translationError(notificationType, language, f.name, "unknown placeholders %v, that %v doesn't have", unknown, defaultLanguage))
+	}
+	var longest string
+	for _, nr := range sampleTranslationNumbers {
+		rendered, err := f.translation.format(sampleTranslationData(expected, actual, nr))
+		if err != nil {
+			return append(problems, translationError(notificationType, language, f.name, "failed to render: %v", err))
+		}
+		if strings.Contains(rendered, "<no value>") {
+			return append(problems, translationError(notificationType, language, f.name, "rendered with missing values: %v", rendered))
+		}
+		if utf8.RuneCountInString(rendered) > utf8.RuneCountInString(longest) {
+			longest = rendered
+		}
+	}
+	if length := utf8.RuneCountInString(longest); f.maxLength > 0 && length > f.maxLength {
+		problems = append(problems, &TranslationProblem{
+			NotificationType: notificationType,
+			Language:         language,
+			Field:            f.name,
+			Message:          fmt.Sprintf("%v characters long, but only %v are shown: %v", length, f.maxLength, longest),
+			Severity:         WarningTranslationProblemSeverity,
+		})
+	}
+
+	return problems
+}
+
+func missingArguments(expected, actual map[string]string) []string {
+	var missing []string
+	for arg := range expected {
+		if _, found := actual[arg]; !found {
+			missing = append(missing, arg)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+func sampleTranslationData(expected, actual map[string]string, nr float64) map[string]any {
+	data := make(map[string]any, len(expected)+len(actual))
+	for _, args := range []map[string]string{expected, actual} {
+		for arg, argType := range args {
+			switch argType {
+			case numberArgType, pluralArgType, selectOrdinalArgType:
+				data[arg] = nr
+			case dateArgType, timeArgType:
+				data[arg] = stdlibtime.Date(2024, stdlibtime.December, 31, 23, 59, 0, 0, stdlibtime.UTC) //nolint:gomnd // It's just a sample.
+			case selectArgType:
+				data[arg] = otherSelector
+			default:
+				if sample, found := sampleTranslationArguments[arg]; found {
+					data[arg] = sample
+				} else {
+					data[arg] = "Sample" + arg
+				}
+			}
+		}
+	}
+
+	return data
+}
+
+func translationError(notificationType NotificationType, language languageCode, field, format string, args ...any) *TranslationProblem {
+	return &TranslationProblem{
+		NotificationType: notificationType,
+		Language:         language,
+		Field:            field,
+		Message:          fmt.Sprintf(format, args...),
+		Severity:         ErrorTranslationProblemSeverity,
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package notifications
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	userActivityBackfillBatchSize = 1000
+)
+
+func (s *viewedNewsSource) Process(ctx context.Context, msg *messagebroker.Message) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline while processing message")
+	}
+	if len(msg.Value) == 0 {
+		return nil
+	}
+	type viewedNews struct {
+		CreatedAt *time.Time `json:"createdAt,omitempty"`
+		UserID    string     `json:"userId,omitempty"`
+	}
+	message := new(viewedNews)
+	if err := json.UnmarshalContext(ctx, msg.Value, message); err != nil {
+		return errors.Wrapf(err, "cannot unmarshal %v into %#v", string(msg.Value), message)
+	}
+	if message.UserID == "" || message.CreatedAt.IsNil() {
+		return nil
+	}
+
+	return errors.Wrapf(s.recordUserActivity(ctx, message.UserID, message.CreatedAt), "failed to recordUserActivity for %#v", message)
+}
+
+// recordUserActivity stops the re-engagement steps of the user, if the activity is newer than the one they're for.
+func (r *repository) recordUserActivity(ctx context.Context, userID string, activeAt *time.Time) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `INSERT INTO user_activity (last_active_at, user_id) VALUES ($1, $2)
+			ON CONFLICT(user_id) DO UPDATE
+			SET last_active_at = EXCLUDED.last_active_at,
+				re_engagement_steps_sent = 0,
+				re_engagement_deferred_until = null
+			WHERE user_activity.last_active_at < EXCLUDED.last_active_at`
+	_, err := storage.Exec(ctx, r.db, sql, activeAt.Time, userID)
+
+	return errors.Wrapf(err, "failed to upsert user_activity for userID:%v, activeAt:%v", userID, activeAt)
+}
+
+// backfillUserActivity records the latest known activity of the users that were neither created nor active since user_activity was introduced.
+// The users without any are considered active now, so that they're not nudged right away. The existing activity is never overwritten.
+func (p *processor) backfillUserActivity(ctx context.Context) error {
+	type userID struct {
+		UserID string
+	}
+	var lastUserID string
+	for ctx.Err() == nil {
+		sql := `SELECT u.user_id
+				FROM users u
+				WHERE u.user_id > $1
+				  AND NOT EXISTS (SELECT 1 FROM user_activity ua WHERE ua.user_id = u.user_id)
+				ORDER BY u.user_id
+				LIMIT $2`
+		batch, err := storage.Select[userID](ctx, p.db, sql, lastUserID, userActivityBackfillBatchSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to select users without activity after %v", lastUserID)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		userIDs := make([]string, 0, len(batch))
+		for _, usr := range batch {
+			userIDs = append(userIDs, usr.UserID)
+		}
+		// The news views are written by the news processor, in the same database.
+		sql = `INSERT INTO user_activity (last_active_at, user_id)
+				SELECT COALESCE(GREATEST(u.created_at, ms.started_at, (SELECT max(nvu.created_at) FROM news_viewed_by_users nvu WHERE nvu.user_id = u.user_id)), $2),
+					   u.user_id
+				FROM users u
+					LEFT JOIN mining_sessions ms ON ms.user_id = u.user_id
+				WHERE u.user_id = ANY($1)
+			   ON CONFLICT DO NOTHING`
+		if _, err = storage.Exec(ctx, p.db, sql, userIDs, time.Now().Time); err != nil {
+			return errors.Wrapf(err, "failed to insert user_activity for userIDs:%#v", userIDs)
+		}
+		lastUserID = userIDs[len(userIDs)-1]
+		if len(batch) < userActivityBackfillBatchSize {
+			return nil
+		}
+	}
+
+	return errors.Wrap(ctx.Err(), "context failed")
+}
+
+func (s *userTableSource) deleteUserActivity(ctx context.Context, userID string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "[deleteUserActivity] context failed")
+	}
+	sql := `DELETE FROM user_activity WHERE user_id = $1`
+	_, err := storage.Exec(ctx, s.db, sql, userID)
+
+	return errors.Wrapf(err, "failed to delete user activity for userID:%v", userID)
+}
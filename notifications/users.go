@@ -19,49 +19,40 @@ import (
 	"github.com/ice-blockchain/wintr/time"
 )
 
-func (r *repository) GetNotificationChannelToggles( //nolint:funlen,gocognit,gocyclo,revive,cyclop // .
+func (r *repository) GetNotificationChannelToggles(
 	ctx context.Context, channel NotificationChannel, userID string,
-) (resp []*NotificationChannelToggle, err error) {
+) ([]*NotificationChannelToggle, error) {
+	return r.getNotificationChannelToggles(ctx, r.db, channel, userID)
+}
+
+func (r *repository) getNotificationChannelToggles(
+	ctx context.Context, db storage.Querier, channel NotificationChannel, userID string,
+) ([]*NotificationChannelToggle, error) {
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
-	resp = r.defaultNotificationChannelToggles(channel)
-	usr, err := r.getUserByID(ctx, userID)
+	resp := r.defaultNotificationChannelToggles(channel)
+	type notificationDomainToggle struct {
+		NotificationDomain NotificationDomain
+		Enabled            bool
+	}
+	sql := `SELECT notification_domain, enabled FROM notification_domain_toggles WHERE user_id = $1 AND notification_channel = $2`
+	domainToggles, err := storage.Select[notificationDomainToggle](ctx, db, sql, userID, channel)
 	if err != nil {
-		if storage.IsErr(err, ErrNotFound) {
-			return resp, nil
-		}
-
-		return nil, errors.Wrapf(err, "failed to get user by id:%v ", userID)
-	}
-	switch channel { //nolint:exhaustive // We don't care about the rest.
-	case EmailNotificationChannel:
-		if usr.DisabledEmailNotificationDomains != nil && len(*usr.DisabledEmailNotificationDomains) > 0 {
-			for _, domain := range *usr.DisabledEmailNotificationDomains {
-				for _, toggle := range resp {
-					if toggle.Type == DisableAllNotificationDomain && domain == AllNotificationDomain {
-						toggle.Enabled = true
-					} else if domain == toggle.Type {
-						toggle.Enabled = false
-					}
-				}
-			}
-		}
-	case PushNotificationChannel:
-		if usr.DisabledPushNotificationDomains != nil && len(*usr.DisabledPushNotificationDomains) > 0 {
-			for _, domain := range *usr.DisabledPushNotificationDomains {
-				for _, toggle := range resp {
-					if toggle.Type == DisableAllNotificationDomain && domain == AllNotificationDomain {
-						toggle.Enabled = true
-					} else if domain == toggle.Type {
-						toggle.Enabled = false
-					}
-				}
+		return nil, errors.Wrapf(err, "failed to select notification_domain_toggles for userID:%v,channel:%v", userID, channel)
+	}
+	for _, domainToggle := range domainToggles {
+		for _, toggle := range resp {
+			if toggle.Type == domainToggle.NotificationDomain {
+				toggle.Enabled = domainToggle.Enabled
 			}
 		}
-	default:
-		log.Panic(fmt.Sprintf("channel `%v` not supported", channel))
 	}
+	overrides, err := getNotificationTypeToggles(ctx, db, channel, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to getNotificationTypeToggles for channel:%v, userID:%v", channel, userID)
+	}
+	applyNotificationTypeToggles(resp, overrides)
 
 	return resp, nil
 }
@@ -79,146 +70,99 @@ func (*repository) defaultNotificationChannelToggles(channel NotificationChannel
 	return resp
 }
 
-func (r *repository) ToggleNotificationChannelDomain( //nolint:funlen,gocognit,gocyclo,revive,cyclop // .
+func (r *repository) ToggleNotificationChannelDomain(
 	ctx context.Context, channel NotificationChannel, domain NotificationDomain, enabled bool, userID string,
 ) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
-	usr, err := r.getUserByID(ctx, userID)
-	if err != nil {
-		if storage.IsErr(err, ErrNotFound) {
-			err = ErrRelationNotFound
-		}
 
-		return errors.Wrapf(err, "failed to get user by id:%v ", userID)
-	}
-	fieldForUpdate := ""
-	var valuesForUpdate *users.Enum[NotificationDomain]
-	switch channel { //nolint:exhaustive // We don't care about the rest.
-	case EmailNotificationChannel: //nolint:dupl // .
-		fieldForUpdate = "disabled_email_notification_domains = $2"
-		if enabled && domain != DisableAllNotificationDomain { //nolint:nestif // .
-			if usr.DisabledEmailNotificationDomains != nil && len(*usr.DisabledEmailNotificationDomains) > 0 {
-				for i, disabledDomain := range *usr.DisabledEmailNotificationDomains {
-					if domain == disabledDomain {
-						(*usr.DisabledEmailNotificationDomains)[i] = ""
-						valuesForUpdate = usr.DisabledEmailNotificationDomains
-
-						break
-					}
-				}
-			}
-		} else {
-			if usr.DisabledEmailNotificationDomains != nil && len(*usr.DisabledEmailNotificationDomains) > 0 {
-				alreadyThere := !enabled && domain == DisableAllNotificationDomain
-				for i, disabledDomain := range *usr.DisabledEmailNotificationDomains {
-					if !enabled && domain == DisableAllNotificationDomain && disabledDomain == AllNotificationDomain {
-						(*usr.DisabledEmailNotificationDomains)[i] = ""
-						valuesForUpdate = usr.DisabledEmailNotificationDomains
-						alreadyThere = true
-
-						break
-					}
-					if (enabled && domain == DisableAllNotificationDomain && disabledDomain == AllNotificationDomain) || domain == disabledDomain {
-						alreadyThere = true
-
-						break
-					}
-				}
-				if !alreadyThere {
-					actualDomain := domain
-					if domain == DisableAllNotificationDomain {
-						actualDomain = AllNotificationDomain
-					}
-					*usr.DisabledEmailNotificationDomains = append(*usr.DisabledEmailNotificationDomains, actualDomain)
-					valuesForUpdate = usr.DisabledEmailNotificationDomains
-				}
-			} else {
-				if !enabled && domain == DisableAllNotificationDomain {
-					break
-				}
-				actualDomain := domain
-				if domain == DisableAllNotificationDomain {
-					actualDomain = AllNotificationDomain
-				}
-				disabledDomains := append(make(users.Enum[NotificationDomain], 0, 1), actualDomain)
-				usr.DisabledEmailNotificationDomains = &disabledDomains
-				valuesForUpdate = usr.DisabledEmailNotificationDomains
-			}
+	return errors.Wrapf(setNotificationChannelToggles(ctx, r.db, channel, []*NotificationChannelToggle{{Type: domain, Enabled: enabled}}, userID),
+		"failed to toggle %v for channel:%v, userID:%v", domain, channel, userID)
+}
+
+func (r *repository) SetNotificationChannelToggles(
+	ctx context.Context, channel NotificationChannel, toggles []*NotificationChannelToggle, userID string,
+) (resp []*NotificationChannelToggle, err error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	err = storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		if err = setNotificationChannelToggles(ctx, conn, channel, toggles, userID); err != nil {
+			return errors.Wrapf(err, "failed to setNotificationChannelToggles for channel:%v, userID:%v", channel, userID)
 		}
-	case PushNotificationChannel: //nolint:dupl // .
-		fieldForUpdate = "disabled_push_notification_domains = $2"
-		if enabled && domain != DisableAllNotificationDomain { //nolint:nestif // .
-			if usr.DisabledPushNotificationDomains != nil && len(*usr.DisabledPushNotificationDomains) > 0 {
-				for i, disabledDomain := range *usr.DisabledPushNotificationDomains {
-					if domain == disabledDomain {
-						(*usr.DisabledPushNotificationDomains)[i] = ""
-						valuesForUpdate = usr.DisabledPushNotificationDomains
-
-						break
-					}
-				}
-			}
-		} else {
-			if usr.DisabledPushNotificationDomains != nil && len(*usr.DisabledPushNotificationDomains) > 0 {
-				alreadyThere := !enabled && domain == DisableAllNotificationDomain
-				for i, disabledDomain := range *usr.DisabledPushNotificationDomains {
-					if !enabled && domain == DisableAllNotificationDomain && disabledDomain == AllNotificationDomain {
-						(*usr.DisabledPushNotificationDomains)[i] = ""
-						valuesForUpdate = usr.DisabledPushNotificationDomains
-						alreadyThere = true
-
-						break
-					}
-					if (enabled && domain == DisableAllNotificationDomain && disabledDomain == AllNotificationDomain) || domain == disabledDomain {
-						alreadyThere = true
-
-						break
-					}
-				}
-				if !alreadyThere {
-					actualDomain := domain
-					if domain == DisableAllNotificationDomain {
-						actualDomain = AllNotificationDomain
-					}
-					*usr.DisabledPushNotificationDomains = append(*usr.DisabledPushNotificationDomains, actualDomain)
-					valuesForUpdate = usr.DisabledPushNotificationDomains
-				}
-			} else {
-				if !enabled && domain == DisableAllNotificationDomain {
-					break
-				}
-				actualDomain := domain
-				if domain == DisableAllNotificationDomain {
-					actualDomain = AllNotificationDomain
-				}
-				disabledDomains := append(make(users.Enum[NotificationDomain], 0, 1), actualDomain)
-				usr.DisabledPushNotificationDomains = &disabledDomains
-				valuesForUpdate = usr.DisabledPushNotificationDomains
+		resp, err = r.getNotificationChannelToggles(ctx, conn, channel, userID)
+
+		return errors.Wrapf(err, "failed to getNotificationChannelToggles for channel:%v, userID:%v", channel, userID)
+	})
+
+	return resp, errors.Wrapf(err, "transaction failed for channel:%v, userID:%v, toggles:%#v", channel, userID, toggles)
+}
+
+//nolint:funlen // Better to be grouped together, so that it's atomic.
+func setNotificationChannelToggles(
+	ctx context.Context, db storage.Execer, channel NotificationChannel, toggles []*NotificationChannelToggle, userID string,
+) error {
+	// The same row can't be changed twice in the same statement, so the last one wins.
+	domains, types := make(map[NotificationDomain]bool, len(toggles)), make(map[NotificationType]*bool)
+	for _, toggle := range toggles {
+		domains[toggle.Type] = toggle.Enabled
+		for _, typeToggle := range toggle.NotificationTypes {
+			var enabled *bool
+			if typeToggle.Overridden {
+				enabled = &typeToggle.Enabled
 			}
+			types[typeToggle.Type] = enabled
 		}
-	default:
-		log.Panic(fmt.Sprintf("channel `%v` not supported", channel))
 	}
-	if valuesForUpdate == nil {
-		return nil
-	}
-	disabledDomains := *(valuesForUpdate)
-	sanitizedDisabledDomains := make(users.Enum[NotificationDomain], 0, len(disabledDomains))
-	for _, notificationDomain := range disabledDomains {
-		if notificationDomain != "" {
-			sanitizedDisabledDomains = append(sanitizedDisabledDomains, notificationDomain)
+	args := []any{time.Now().Time, userID, channel}
+	ctes := make([]string, 0, 3) //nolint:gomnd // 1 per table operation.
+	if len(domains) != 0 {
+		values := make([]string, 0, len(domains))
+		for domain, enabled := range domains {
+			args = append(args, domain, enabled)
+			values = append(values, fmt.Sprintf("($1,$2,$3,$%v,$%v)", len(args)-1, len(args)))
 		}
-	}
-	valuesForUpdate = &sanitizedDisabledDomains
-	sql := fmt.Sprintf(`UPDATE users SET %v where user_id = $1`, fieldForUpdate)
-	if rowsUpdated, tErr := storage.Exec(ctx, r.db, sql, append([]any{userID}, valuesForUpdate)...); rowsUpdated == 0 || tErr != nil {
-		if rowsUpdated == 0 && tErr == nil {
-			tErr = ErrRelationNotFound
+		ctes = append(ctes, fmt.Sprintf(`upserted_domains AS (
+			INSERT INTO notification_domain_toggles (UPDATED_AT, USER_ID, NOTIFICATION_CHANNEL, NOTIFICATION_DOMAIN, ENABLED) VALUES %v
+			ON CONFLICT(USER_ID, NOTIFICATION_CHANNEL, NOTIFICATION_DOMAIN) DO UPDATE
+			SET ENABLED = EXCLUDED.ENABLED,
+				UPDATED_AT = EXCLUDED.UPDATED_AT
+			RETURNING 1)`, strings.Join(values, ",")))
+	}
+	values, resetTypes := make([]string, 0, len(types)), make([]string, 0, len(types))
+	for notificationType, enabled := range types {
+		if enabled == nil {
+			resetTypes = append(resetTypes, string(notificationType))
+
+			continue
 		}
-
-		return errors.Wrapf(tErr, "failed to update users for userID:%v,ops:%#v with values %#v", userID, fieldForUpdate, valuesForUpdate)
+		args = append(args, notificationType, *enabled)
+		values = append(values, fmt.Sprintf("($1,$2,$3,$%v,$%v)", len(args)-1, len(args)))
+	}
+	if len(values) != 0 {
+		ctes = append(ctes, fmt.Sprintf(`upserted_types AS (
+			INSERT INTO notification_type_toggles (UPDATED_AT, USER_ID, NOTIFICATION_CHANNEL, NOTIFICATION_TYPE, ENABLED) VALUES %v
+			ON CONFLICT(USER_ID, NOTIFICATION_CHANNEL, NOTIFICATION_TYPE) DO UPDATE
+			SET ENABLED = EXCLUDED.ENABLED,
+				UPDATED_AT = EXCLUDED.UPDATED_AT
+			RETURNING 1)`, strings.Join(values, ",")))
+	}
+	if len(resetTypes) != 0 {
+		args = append(args, resetTypes)
+		ctes = append(ctes, fmt.Sprintf(`reset_types AS (
+			DELETE FROM notification_type_toggles
+			WHERE user_id = $2
+			  AND notification_channel = $3
+			  AND notification_type = ANY($%v)
+			RETURNING 1)`, len(args)))
+	}
+	if len(ctes) == 0 {
+		return nil
+	}
+	sql := fmt.Sprintf(`WITH %v SELECT 1`, strings.Join(ctes, ",\n"))
+	if _, err := storage.Exec(ctx, db, sql, args...); err != nil {
+		return errors.Wrapf(err, "failed to set notification toggles for userID:%v,channel:%v,toggles:%#v", userID, channel, toggles)
 	}
 
 	return nil
@@ -244,6 +188,16 @@ func (s *userTableSource) Process(ctx context.Context, msg *messagebroker.Messag
 	if err := s.upsertUser(ctx, snapshot); err != nil {
 		return errors.Wrapf(err, "failed to upsert:%#v", snapshot)
 	}
+	if (snapshot.Before == nil || snapshot.Before.ID == "") && snapshot.User.CreatedAt != nil {
+		if err := s.recordUserActivity(ctx, snapshot.User.ID, snapshot.User.CreatedAt); err != nil {
+			return errors.Wrapf(err, "failed to recordUserActivity for:%#v", snapshot)
+		}
+	}
+	if snapshot.User.Email != "" && snapshot.Before != nil && snapshot.Before.ID != "" && !strings.EqualFold(snapshot.Before.Email, snapshot.User.Email) {
+		if err := clearEmailSuppression(ctx, s.db, snapshot.User.Email); err != nil {
+			return errors.Wrapf(err, "failed to clearEmailSuppression for:%#v", snapshot)
+		}
+	}
 
 	return errors.Wrapf(s.sendNewReferralNotification(ctx, snapshot), "failed to sendNewReferralNotification for :%#v", snapshot)
 }
@@ -262,8 +216,10 @@ func (s *userTableSource) upsertUser(ctx context.Context, us *users.UserSnapshot
                    REFERRED_BY,
                    PHONE_NUMBER_HASH,
                    LANGUAGE,
-                   USER_ID
-    ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+                   USER_ID,
+                   COUNTRY,
+                   CREATED_AT
+    ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
     ON CONFLICT(user_id)
       DO UPDATE
       	SET        PHONE_NUMBER = EXCLUDED.PHONE_NUMBER,
@@ -274,7 +230,13 @@ func (s *userTableSource) upsertUser(ctx context.Context, us *users.UserSnapshot
                    PROFILE_PICTURE_NAME = EXCLUDED.PROFILE_PICTURE_NAME,
                    REFERRED_BY = EXCLUDED.REFERRED_BY,
                    PHONE_NUMBER_HASH = EXCLUDED.PHONE_NUMBER_HASH,
-                   LANGUAGE = EXCLUDED.LANGUAGE`
+                   LANGUAGE = EXCLUDED.LANGUAGE,
+                   COUNTRY = EXCLUDED.COUNTRY,
+                   CREATED_AT = COALESCE(USERS.CREATED_AT, EXCLUDED.CREATED_AT)`
+	var createdAt *stdlibtime.Time
+	if us.CreatedAt != nil {
+		createdAt = us.CreatedAt.Time
+	}
 	_, err := storage.Exec(ctx, s.db, sql,
 		us.PhoneNumber,
 		us.Email,
@@ -286,6 +248,8 @@ func (s *userTableSource) upsertUser(ctx context.Context, us *users.UserSnapshot
 		us.PhoneNumberHash,
 		us.Language,
 		us.ID,
+		strings.ToUpper(us.Country),
+		createdAt,
 	)
 
 	return errors.Wrapf(err, "failed to upsert %#v", us)
@@ -300,7 +264,15 @@ func (s *userTableSource) deleteUser(ctx context.Context, us *users.UserSnapshot
 		return errors.Wrapf(err, "failed to delete user:%#v", us)
 	}
 
-	return errors.Wrapf(s.deleteDeviceMetadata(ctx, us.Before.ID), "failed to delete user:%#v", us)
+	if err := s.deleteDeviceMetadata(ctx, us.Before.ID); err != nil {
+		return errors.Wrapf(err, "failed to delete user:%#v", us)
+	}
+
+	if err := s.deleteMiningSession(ctx, us.Before.ID); err != nil {
+		return errors.Wrapf(err, "failed to delete user:%#v", us)
+	}
+
+	return errors.Wrapf(s.deleteUserActivity(ctx, us.Before.ID), "failed to delete user:%#v", us)
 }
 
 func (s *userTableSource) deleteDeviceMetadata(ctx context.Context, userID string) error {
@@ -336,17 +308,35 @@ func (u *deviceMetadataTableSource) Process(ctx context.Context, msg *messagebro
 	if err := json.UnmarshalContext(ctx, msg.Value, snapshot); err != nil {
 		return errors.Wrapf(err, "cannot unmarshal %v into %#v", string(msg.Value), snapshot)
 	}
+	type appVersion struct {
+		ReadableVersion string `json:"readableVersion,omitempty"`
+		TZ              string `json:"tz,omitempty"`
+	}
+	versions := new(struct {
+		Before *appVersion `json:"before,omitempty"`
+		appVersion
+	})
+	if err := json.UnmarshalContext(ctx, msg.Value, versions); err != nil {
+		return errors.Wrapf(err, "cannot unmarshal %v into %#v", string(msg.Value), versions)
+	}
 	if snapshot.DeviceMetadata == nil || snapshot.DeviceMetadata.ID.UserID == "" ||
-		(snapshot.Before != nil && snapshot.Before.PushNotificationToken == snapshot.DeviceMetadata.PushNotificationToken) {
+		(snapshot.Before != nil && snapshot.Before.PushNotificationToken == snapshot.DeviceMetadata.PushNotificationToken &&
+			versions.Before != nil && versions.Before.ReadableVersion == versions.ReadableVersion && versions.Before.TZ == versions.TZ) {
 		return nil
 	}
-	sql := `INSERT INTO device_metadata (USER_ID, DEVICE_UNIQUE_ID, PUSH_NOTIFICATION_TOKEN) VALUES ($1, $2, $3)
+	sql := `INSERT INTO device_metadata (USER_ID, DEVICE_UNIQUE_ID, PUSH_NOTIFICATION_TOKEN, APP_VERSION, TZ, UPDATED_AT) VALUES ($1, $2, $3, $4, $5, $6)
 			ON CONFLICT(USER_ID, DEVICE_UNIQUE_ID) DO UPDATE
-			SET PUSH_NOTIFICATION_TOKEN = EXCLUDED.PUSH_NOTIFICATION_TOKEN`
+			SET PUSH_NOTIFICATION_TOKEN = EXCLUDED.PUSH_NOTIFICATION_TOKEN,
+				APP_VERSION = EXCLUDED.APP_VERSION,
+				TZ = EXCLUDED.TZ,
+				UPDATED_AT = EXCLUDED.UPDATED_AT`
 	params := []any{
 		snapshot.ID.UserID,
 		snapshot.ID.DeviceUniqueID,
 		snapshot.PushNotificationToken,
+		versions.ReadableVersion,
+		versions.TZ,
+		time.Now().Time,
 	}
 	_, err := storage.Exec(ctx, u.db, sql, params...)
 
@@ -416,20 +406,143 @@ func (r *repository) PingUser(ctx context.Context, userID string) error { //noli
 	return nil
 }
 
-func (r *repository) sendUserPingMessage(ctx context.Context, up *UserPing) error {
-	valueBytes, err := json.MarshalContext(ctx, up)
+func (r *repository) PingReferrals(ctx context.Context) ([]*UserPingResult, error) { //nolint:funlen // .
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected context deadline")
+	}
+	type referralPing struct {
+		// The previous one, for the pinged referrals.
+		PingCooldownEndedAt *time.Time
+		UserID              string
+		Pinged              bool
+	}
+	reqUserID := requestingUserID(ctx)
+	now := time.Now()
+	newPingCooldownEndsAt := time.New(now.Add(r.cfg.PingCooldown))
+	// The cooldowns are all applied by this single statement, so that concurrent pings, in bulk or not, can't ping anyone twice.
+	// The skipped referrals are read from the same snapshot, so the ones not in their cooldown were either locked or over the limit.
+	sql := fmt.Sprintf(`WITH eligible AS (
+							SELECT user_id, last_ping_cooldown_ended_at
+							FROM users
+							WHERE referred_by = $2
+							  AND COALESCE(last_ping_cooldown_ended_at, to_timestamp(0)) <= $3
+							ORDER BY user_id
+							LIMIT %[1]v
+							FOR UPDATE SKIP LOCKED
+						), pinged AS (
+							UPDATE users u
+							   SET last_ping_cooldown_ended_at = $1
+							FROM eligible e
+							WHERE u.user_id = e.user_id
+							RETURNING e.last_ping_cooldown_ended_at AS ping_cooldown_ended_at, u.user_id
+						), skipped AS (
+							SELECT last_ping_cooldown_ended_at AS ping_cooldown_ended_at, user_id
+							FROM users
+							WHERE referred_by = $2
+							  AND user_id NOT IN (SELECT user_id FROM eligible)
+							ORDER BY COALESCE(last_ping_cooldown_ended_at, to_timestamp(0)) <= $3 DESC, user_id
+							LIMIT %[1]v
+						)
+						SELECT ping_cooldown_ended_at, user_id, TRUE AS pinged FROM pinged
+						UNION ALL
+						SELECT ping_cooldown_ended_at, user_id, FALSE AS pinged FROM skipped`, maxReferralsPingedAtOnce)
+	referralPings, err := storage.ExecMany[referralPing](ctx, r.db, sql, newPingCooldownEndsAt.Time, reqUserID, now.Time)
 	if err != nil {
-		return errors.Wrapf(err, "failed to marshal %#v", up)
+		return nil, errors.Wrapf(err, "failed to update users to set last_ping_cooldown_ended_at for the referrals of userID:%v", reqUserID)
+	}
+	referrals, skipped := make([]*referralPing, 0, len(referralPings)), make([]*referralPing, 0, len(referralPings))
+	for _, referral := range referralPings {
+		if referral.Pinged {
+			referrals = append(referrals, referral)
+		} else {
+			skipped = append(skipped, referral)
+		}
+	}
+	results := make([]*UserPingResult, 0, len(referralPings))
+	for start := 0; start < len(referrals); start += userPingMessagesBatchSize {
+		batch := referrals[start:min(start+userPingMessagesBatchSize, len(referrals))]
+		ups := make([]*UserPing, 0, len(batch))
+		for _, referral := range batch {
+			ups = append(ups, &UserPing{UserID: referral.UserID, PingedBy: reqUserID, LastPingCooldownEndedAt: newPingCooldownEndsAt})
+		}
+		for ix, sErr := range r.sendUserPingMessages(ctx, ups) {
+			result := &UserPingResult{UserID: batch[ix].UserID, LastPingCooldownEndedAt: newPingCooldownEndsAt, Pinged: sErr == nil}
+			if sErr != nil {
+				rErr := r.rollbackPingCooldown(ctx, batch[ix].UserID, batch[ix].PingCooldownEndedAt, newPingCooldownEndsAt)
+				if rErr == nil {
+					result.LastPingCooldownEndedAt = batch[ix].PingCooldownEndedAt
+				}
+				result.Error = "failed to ping the user, try again"
+				log.Error(multierror.Append( //nolint:wrapcheck // Not needed.
+					errors.Wrapf(sErr, "failed to sendUserPingMessage %#v", ups[ix]),
+					errors.Wrapf(rErr, "[rollback] failed to rollbackPingCooldown for %#v", batch[ix]),
+				).ErrorOrNil())
+			}
+			results = append(results, result)
+		}
+	}
+	for _, referral := range skipped {
+		result := &UserPingResult{UserID: referral.UserID, LastPingCooldownEndedAt: referral.PingCooldownEndedAt}
+		switch {
+		case referral.PingCooldownEndedAt != nil && referral.PingCooldownEndedAt.After(*now.Time):
+			result.SkipReason = InCooldownUserPingSkipReason
+		case len(referrals) == maxReferralsPingedAtOnce:
+			result.SkipReason = LimitReachedUserPingSkipReason
+		default:
+			result.SkipReason = ConcurrentlyPingedUserPingSkipReason
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// rollbackPingCooldown restores the previous ping cooldown of the user, if nobody changed it meanwhile, so that they can be pinged again.
+func (r *repository) rollbackPingCooldown(ctx context.Context, userID string, previous, current *time.Time) error {
+	var previousTime *stdlibtime.Time
+	if previous != nil {
+		previousTime = previous.Time
+	}
+	sql := `UPDATE users 
+			   SET last_ping_cooldown_ended_at = $1
+		    WHERE user_id = $2
+			  AND last_ping_cooldown_ended_at = $3`
+	_, err := storage.Exec(ctx, r.db, sql, previousTime, userID, current.Time)
+
+	return errors.Wrapf(err, "failed to update users to set last_ping_cooldown_ended_at to %v for userID:%v", previous, userID)
+}
+
+func (r *repository) sendUserPingMessage(ctx context.Context, up *UserPing) error {
+	return r.sendUserPingMessages(ctx, []*UserPing{up})[0]
+}
+
+// sendUserPingMessages sends the messages all at once and waits for all of them, returning the error of each, in the same order.
+func (r *repository) sendUserPingMessages(ctx context.Context, ups []*UserPing) []error {
+	errs := make([]error, len(ups))
+	responders := make([]chan error, len(ups))
+	for ix, up := range ups {
+		valueBytes, err := json.MarshalContext(ctx, up)
+		if err != nil {
+			errs[ix] = errors.Wrapf(err, "failed to marshal %#v", up)
+
+			continue
+		}
+		msg := &messagebroker.Message{
+			Headers: map[string]string{"producer": "husky"},
+			Key:     up.UserID,
+			Topic:   r.cfg.MessageBroker.Topics[1].Name,
+			Value:   valueBytes,
+		}
+		responders[ix] = make(chan error, 1)
+		r.mb.SendMessage(ctx, msg, responders[ix])
 	}
-	msg := &messagebroker.Message{
-		Headers: map[string]string{"producer": "husky"},
-		Key:     up.UserID,
-		Topic:   r.cfg.MessageBroker.Topics[1].Name,
-		Value:   valueBytes,
+	for ix, responder := range responders {
+		if responder == nil {
+			continue
+		}
+		errs[ix] = errors.Wrapf(<-responder, "failed to send `%v` message to broker", r.cfg.MessageBroker.Topics[1].Name)
+		close(responder)
 	}
-	responder := make(chan error, 1)
-	defer close(responder)
-	r.mb.SendMessage(ctx, msg, responder)
 
-	return errors.Wrapf(<-responder, "failed to send `%v` message to broker", msg.Topic)
+	return errs
 }